@@ -5,6 +5,9 @@
 @host 31.97.190.90:8080
 @BasePath /
 @schemes http https
+@securityDefinitions.apikey BearerAuth
+@in header
+@name Authorization
 */
 package main
 
@@ -14,8 +17,10 @@ import (
 	"os"
 
 	_ "autobackcom/docs" // import docs để swagger serve được
+	"autobackcom/internal/api"
 	"autobackcom/internal/cronjob"
 	"autobackcom/internal/di"
+	"autobackcom/internal/models"
 	"autobackcom/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +44,9 @@ func main() {
 
 	// Load env trước khi build container
 	_ = godotenv.Load()
+	if err := api.LoadJWTSecret(); err != nil {
+		logrus.Fatal(err)
+	}
 
 	c, err := di.BuildContainer()
 	if err != nil {
@@ -61,17 +69,54 @@ func main() {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,X-Requested-With,X-MBX-APIKEY")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
 		}
 		c.Next()
 	})
-	r.POST("/register", appHandlers.RegisterHandler)
-	r.POST("/orders", appHandlers.GetOrdersHandler)
-	r.POST("/fetch-trades-all-user", appHandlers.FetchAllTradesHandler)
+	// Route public
+	r.POST("/signup", appHandlers.SignupHandler)
+	r.POST("/login", appHandlers.LoginHandler)
+	r.POST("/login/apikey", appHandlers.APIKeyLoginHandler)
+	r.GET("/exchanges", appHandlers.ListExchangesHandler)
 	r.GET("/swagger/*any", gin.WrapF(httpSwagger.WrapHandler))
+
+	// Các route còn lại yêu cầu JWT
+	authorized := r.Group("/", api.JWTAuthMiddleware())
+	authorized.POST("/register", appHandlers.RegisterHandler)
+	authorized.GET("/accounts", appHandlers.ListAccountsHandler)
+	authorized.PUT("/accounts/:id", appHandlers.UpdateAccountKeysHandler)
+	authorized.PATCH("/accounts/:id", appHandlers.UpdateAccountStatusHandler)
+	authorized.DELETE("/accounts/:id", appHandlers.DeleteAccountHandler)
+	authorized.POST("/orders", appHandlers.GetOrdersHandler)
+	authorized.POST("/orders/aggregated", appHandlers.GetAggregatedOrdersHandler)
+	authorized.POST("/orders/history", appHandlers.GetOrderHistoryHandler)
+	authorized.POST("/rebates", appHandlers.GetRebatesHandler)
+	authorized.POST("/fees", appHandlers.GetFeesHandler)
+	authorized.POST("/incomes", appHandlers.GetIncomesHandler)
+	authorized.POST("/incomes/summary", appHandlers.GetIncomeSummaryHandler)
+	authorized.POST("/transfers", appHandlers.GetTransfersHandler)
+	authorized.POST("/balances", appHandlers.GetBalanceSnapshotsHandler)
+	authorized.POST("/balances/equity", appHandlers.GetDailyEquityHandler)
+
+	// Route quản trị: operator xem và đồng bộ tài khoản, admin quản lý gói hoàn phí và role
+	admin := r.Group("/admin", api.JWTAuthMiddleware(), api.RequireRole(models.RoleOperator, models.RoleAdmin))
+	admin.GET("/accounts", appHandlers.AdminListAccountsHandler)
+	admin.GET("/accounts/:id/sync-status", appHandlers.AdminSyncStatusHandler)
+	admin.POST("/accounts/:id/sync", appHandlers.AdminSyncAccountHandler)
+	admin.POST("/accounts/:id/rebates/calculate", appHandlers.CalculateRebateHandler)
+	admin.PATCH("/accounts/:id/status", appHandlers.AdminUpdateAccountStatusHandler)
+	admin.POST("/fetch-trades-all-user", appHandlers.FetchAllTradesHandler)
+	adminOnly := admin.Group("/", api.RequireRole(models.RoleAdmin))
+	adminOnly.PUT("/users/:id/role", appHandlers.AdminSetUserRoleHandler)
+	adminOnly.GET("/rate-plans", appHandlers.ListRatePlansHandler)
+	adminOnly.POST("/rate-plans", appHandlers.CreateRatePlanHandler)
+	adminOnly.GET("/rate-plans/:id", appHandlers.GetRatePlanHandler)
+	adminOnly.PUT("/rate-plans/:id", appHandlers.UpdateRatePlanHandler)
+	adminOnly.DELETE("/rate-plans/:id", appHandlers.DeleteRatePlanHandler)
+	adminOnly.POST("/rate-plans/:id/assign", appHandlers.AssignRatePlanHandler)
 	// Đăng ký cronjob lấy trade history định kỳ
 	err = c.Invoke(func(ths *services.TradeHistoryService) {
 		go func() {
@@ -87,6 +132,50 @@ func main() {
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	// Đăng ký cronjob đồng bộ income futures định kỳ
+	err = c.Invoke(func(is *services.IncomeService) {
+		cronjob.StartIncomeCron(context.Background(), is)
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	// Đăng ký cronjob đồng bộ lịch sử order định kỳ
+	err = c.Invoke(func(ohs *services.OrderHistoryService) {
+		cronjob.StartOrderHistoryCron(context.Background(), ohs)
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	// Đăng ký cronjob đồng bộ lịch sử nạp/rút/chuyển tiền định kỳ
+	err = c.Invoke(func(ts *services.TransferService) {
+		cronjob.StartTransferCron(context.Background(), ts)
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	// Đăng ký cronjob chụp số dư tài khoản định kỳ
+	err = c.Invoke(func(bs *services.BalanceService) {
+		cronjob.StartBalanceSnapshotCron(context.Background(), bs)
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	// Mở user data stream để nhận fill theo thời gian thực, tắt bằng USER_STREAM_ENABLED=false
+	if services.UserStreamsEnabled() {
+		err = c.Invoke(func(uss *services.UserStreamService) {
+			go uss.Run(context.Background())
+		})
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
+	// Đăng ký cronjob tính hoàn phí hàng tháng
+	err = c.Invoke(func(rs *services.RebateService) {
+		cronjob.StartRebateCron(context.Background(), rs)
+	})
+	if err != nil {
+		logrus.Fatal(err)
+	}
 	logrus.Info("Server starting on :8080")
 	log.Fatal(r.Run(":8080"))
 }
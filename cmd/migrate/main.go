@@ -0,0 +1,53 @@
+// Command migrate chạy các migration dữ liệu một lần.
+//
+//	go run ./cmd/migrate            # liệt kê migration
+//	go run ./cmd/migrate <tên>      # chạy migration
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"autobackcom/internal/migrations"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	_ = godotenv.Load()
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: migrate <name>")
+		for _, m := range migrations.All() {
+			fmt.Printf("  %s\t%s\n", m.Name, m.Description)
+		}
+		return
+	}
+	migration, err := migrations.Get(os.Args[1])
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		logrus.Fatal("MONGODB_URI is not set in environment")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		logrus.WithField("error", err).Fatal("Failed to connect to MongoDB")
+	}
+	defer client.Disconnect(ctx)
+
+	logrus.WithField("migration", migration.Name).Info("Running migration")
+	if err := migration.Run(ctx, client.Database("exchange_db")); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"migration": migration.Name,
+			"error":     err,
+		}).Fatal("Migration failed")
+	}
+	logrus.WithField("migration", migration.Name).Info("Migration finished")
+}
@@ -0,0 +1,58 @@
+// Command rotatekeys mã hóa lại API key/secret của mọi tài khoản bằng key active của keyring.
+//
+// Quy trình xoay key:
+//  1. Thêm key mới vào ENCRYPTION_KEYS, đặt ENCRYPTION_ACTIVE_KEY_ID là key mới và khởi động lại service
+//  2. go run ./cmd/rotatekeys (service vẫn chạy bình thường)
+//  3. Khi lệnh báo không còn tài khoản lỗi/xung đột, gỡ key cũ khỏi ENCRYPTION_KEYS
+package main
+
+import (
+	"context"
+	"os"
+
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	_ = godotenv.Load()
+	keyring, err := utils.LoadKeyring(os.Getenv("ENCRYPTION_KEYS"), os.Getenv("ENCRYPTION_ACTIVE_KEY_ID"), os.Getenv("ENCRYPTION_KEY"))
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		logrus.Fatal("MONGODB_URI is not set in environment")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		logrus.WithField("error", err).Fatal("Failed to connect to MongoDB")
+	}
+	defer client.Disconnect(ctx)
+
+	accountRepo := repositories.NewRegisteredAccountRepository(client, "exchange_db", "registered_accounts")
+	rotation := services.NewKeyRotationService(accountRepo, keyring)
+	logrus.WithField("active_key_id", keyring.ActiveKeyID()).Info("Re-encrypting account secrets")
+	result, err := rotation.ReencryptAll(ctx)
+	if err != nil {
+		logrus.WithField("error", err).Fatal("Key rotation failed")
+	}
+	logrus.WithFields(logrus.Fields{
+		"total":       result.Total,
+		"reencrypted": result.Reencrypted,
+		"skipped":     result.Skipped,
+		"conflicts":   result.Conflicts,
+		"failed":      result.Failed,
+	}).Info("Key rotation finished")
+	if result.Conflicts > 0 || result.Failed > 0 {
+		os.Exit(1)
+	}
+}
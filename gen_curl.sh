@@ -1,19 +1,89 @@
 #!/bin/bash
-# gen_curl.sh - Script gọi API register và orders
+# gen_curl.sh - Script gọi API signup, login, register và orders
 
 API_URL="http://localhost:8080"
 
-# Đăng ký user mới
+# Tạo user mới, response trả về token
+curl -X POST "$API_URL/signup" \
+  -H "Content-Type: application/json" \
+  -d '{"username": "testuser", "password": "YOUR_PASSWORD"}'
+
+echo "\n---"
+
+# Đăng nhập bằng username/mật khẩu để lấy token mới
+curl -X POST "$API_URL/login" \
+  -H "Content-Type: application/json" \
+  -d '{"username": "testuser", "password": "YOUR_PASSWORD"}'
+
+echo "\n---"
+
+# Thêm tài khoản exchange cho user (cần JWT)
+# Thay YOUR_JWT_TOKEN bằng token thực tế
 curl -X POST "$API_URL/register" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"exchange": "binance", "market": "spot", "apikey": "YOUR_API_KEY", "secret": "YOUR_SECRET"}'
+
+echo "\n---"
+
+# Danh sách tài khoản exchange của user
+curl -X GET "$API_URL/accounts" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN"
+
+echo "\n---"
+
+# Lấy danh sách orders, bỏ trống registeredAccountID để lấy order của mọi tài khoản
+curl -X POST "$API_URL/orders" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID"}'
+
+echo "\n---"
+
+# Order gộp từ các fill (tổng khối lượng, giá trung bình, phí theo asset)
+curl -X POST "$API_URL/orders/aggregated" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
   -H "Content-Type: application/json" \
-  -d '{"username": "testuser", "password": "testpass"}'
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID"}'
 
 echo "\n---"
 
-# Lấy danh sách orders (cần JWT nếu API yêu cầu)
-# Thay YOUR_JWT_TOKEN bằng token thực tế nếu cần
-curl -X GET "$API_URL/orders" \
+# Lịch sử order (kể cả order bị hủy) kèm fill, lọc theo symbol/status/thời gian, phân trang theo page/limit
+curl -X POST "$API_URL/orders/history" \
   -H "Authorization: Bearer YOUR_JWT_TOKEN" \
-  -H "Content-Type: application/json"
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID", "symbol": "BTCUSDT", "status": "CANCELED", "from": "2025-01-01T00:00:00Z", "to": "2025-02-01T00:00:00Z", "page": 1, "limit": 100}'
+
+echo "\n---"
+
+# Tổng hợp income futures (PnL đã chốt, funding, phí) theo loại và asset
+curl -X POST "$API_URL/incomes/summary" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID", "from": "2025-01-01T00:00:00Z", "to": "2025-02-01T00:00:00Z"}'
+
+echo "\n---"
+
+# Lịch sử nạp/rút/chuyển tiền nội bộ của tài khoản, type rỗng là mọi loại (DEPOSIT, WITHDRAWAL, TRANSFER)
+curl -X POST "$API_URL/transfers" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID", "type": "DEPOSIT", "from": "2025-01-01T00:00:00Z", "to": "2025-02-01T00:00:00Z"}'
+
+echo "\n---"
+
+# Snapshot số dư của tài khoản trong khoảng thời gian
+curl -X POST "$API_URL/balances" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID", "from": "2025-01-01T00:00:00Z", "to": "2025-02-01T00:00:00Z"}'
+
+echo "\n---"
+
+# Equity theo ngày quy đổi sang USDT
+curl -X POST "$API_URL/balances/equity" \
+  -H "Authorization: Bearer YOUR_JWT_TOKEN" \
+  -H "Content-Type: application/json" \
+  -d '{"registeredAccountID": "YOUR_ACCOUNT_ID", "from": "2025-01-01T00:00:00Z", "to": "2025-02-01T00:00:00Z"}'
 
 echo "\n---"
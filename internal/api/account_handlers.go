@@ -0,0 +1,272 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/secrets"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ListAccountsHandler godoc
+// @Summary Danh sách tài khoản exchange của user
+// @Description Lấy các tài khoản exchange thuộc user đang đăng nhập
+// @Tags registered_accounts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.AccountsResponse}
+// @Failure 401,500 {object} dto.APIResponse
+// @Router /accounts [get]
+func ListAccountsHandler(accountRepo *repositories.RegisteredAccountRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := currentUserObjectID(c)
+		if err != nil {
+			c.JSON(401, utils.Error("Invalid token"))
+			return
+		}
+		accounts, err := accountRepo.GetRegisteredAccountsByUserID(c.Request.Context(), userID)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"user_id": userID.Hex(),
+				"error":   err,
+			}).Error("Failed to get registered accounts")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		data := make([]dto.RegisteredAccountResponse, 0, len(accounts))
+		for _, account := range accounts {
+			data = append(data, dto.RegisteredAccountResponse{
+				ID:          account.ID.Hex(),
+				Exchange:    account.Exchange,
+				Market:      account.Market,
+				IsTestnet:   account.IsTestnet,
+				Status:      account.EffectiveStatus(),
+				ReferrerID:  account.ReferrerID,
+				Permissions: account.Permissions,
+			})
+		}
+		c.JSON(200, utils.Success(dto.AccountsResponse{Status: "ok", Data: data}))
+	}
+}
+
+// UpdateAccountKeysHandler godoc
+// @Summary Thay API key của tài khoản exchange
+// @Description Kiểm tra key mới với exchange, mã hóa lại và bật lại đồng bộ cho tài khoản
+// @Tags registered_accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Param body body dto.UpdateAccountKeysRequest true "API key/secret mới, kèm passphrase với OKX"
+// @Success 200 {object} dto.APIResponse{data=dto.RegisteredAccountResponse}
+// @Failure 400,401,403,404,422,500,502 {object} dto.APIResponse
+// @Router /accounts/{id} [put]
+func UpdateAccountKeysHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService, secretStore secrets.SecretStore, registry *exchanges.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := requireAccountOwner(c, accountRepo, c.Param("id"))
+		if !ok {
+			return
+		}
+		var req dto.UpdateAccountKeysRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.APIKey == "" || req.Secret == "" {
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		if adapter, ok := registry.Adapter(account.Exchange); ok && adapter.RequiresPassphrase && req.Passphrase == "" {
+			c.JSON(400, utils.Error("Thiếu passphrase của API key"))
+			return
+		}
+		creds := secrets.Credentials{APIKey: req.APIKey, Secret: req.Secret, Passphrase: req.Passphrase}
+		permissions, err := clientManager.ValidateKey(c.Request.Context(), account.Exchange, account.Market, creds, account.IsTestnet)
+		if err != nil {
+			writeKeyValidationError(c, account.Username, err)
+			return
+		}
+		previous := account
+		if err := secretStore.Seal(c.Request.Context(), &account, creds); err != nil {
+			logrus.WithField("error", err).Error("Encryption error")
+			c.JSON(500, utils.Error("Lỗi mã hóa"))
+			return
+		}
+		account.Permissions = &permissions
+		// Key mới đã hợp lệ nên bật lại đồng bộ nếu tài khoản bị tắt do key lỗi
+		if account.EffectiveStatus() == models.AccountStatusDisabled {
+			account.Status = models.AccountStatusActive
+		}
+		if err := accountRepo.UpdateCredentials(c.Request.Context(), account); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"error":                 err,
+			}).Error("Failed to update registered account")
+			// Secret vừa ghi ở backend mới không được tham chiếu vì document vẫn trỏ tới backend cũ
+			if previous.SecretBackend != account.SecretBackend {
+				if err := secretStore.Delete(c.Request.Context(), account); err != nil {
+					logrus.WithFields(logrus.Fields{
+						"registered_account_id": account.ID.Hex(),
+						"error":                 err,
+					}).Error("Failed to delete orphaned secret")
+				}
+			}
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		clientManager.InvalidateClient(account.ID)
+		// Stream đang chạy bằng key cũ, lần refresh sau mở lại bằng key mới
+		userStreams.Stop(account.ID)
+		// Secret cũ ở backend ngoài không còn được tham chiếu sau khi đổi backend
+		if previous.SecretBackend != account.SecretBackend {
+			if err := secretStore.Delete(c.Request.Context(), previous); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to delete previous secret")
+			}
+		}
+		logrus.WithField("registered_account_id", account.ID.Hex()).Info("Registered account keys rotated")
+		c.JSON(200, utils.Success(dto.RegisteredAccountResponse{
+			ID:          account.ID.Hex(),
+			Exchange:    account.Exchange,
+			Market:      account.Market,
+			IsTestnet:   account.IsTestnet,
+			Status:      account.EffectiveStatus(),
+			ReferrerID:  account.ReferrerID,
+			Permissions: account.Permissions,
+		}))
+	}
+}
+
+// UpdateAccountStatusHandler godoc
+// @Summary Tạm dừng hoặc tiếp tục đồng bộ tài khoản
+// @Description User chỉ được chuyển giữa active và paused, tài khoản bị operator tắt cần thay key để bật lại
+// @Tags registered_accounts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Param body body dto.UpdateAccountStatusRequest true "Trạng thái mới (active, paused)"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,409,500 {object} dto.APIResponse
+// @Router /accounts/{id} [patch]
+func UpdateAccountStatusHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := requireAccountOwner(c, accountRepo, c.Param("id"))
+		if !ok {
+			return
+		}
+		var req dto.UpdateAccountStatusRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		if req.Status != models.AccountStatusActive && req.Status != models.AccountStatusPaused {
+			c.JSON(400, utils.Error("Trạng thái không hợp lệ"))
+			return
+		}
+		if account.EffectiveStatus() == models.AccountStatusDisabled {
+			c.JSON(409, utils.Error("Tài khoản đã bị tắt, hãy cập nhật API key để bật lại"))
+			return
+		}
+		updateAccountStatus(c, accountRepo, clientManager, userStreams, account, req.Status)
+	}
+}
+
+// updateAccountStatus lưu trạng thái mới, bỏ client đã cache và đóng user data stream nếu tài khoản không còn active
+func updateAccountStatus(c *gin.Context, accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService, account models.RegisteredAccount, status string) {
+	if err := accountRepo.UpdateStatus(c.Request.Context(), account.ID, status); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": account.ID.Hex(),
+			"error":                 err,
+		}).Error("Failed to update registered account status")
+		c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+		return
+	}
+	clientManager.InvalidateClient(account.ID)
+	if status != models.AccountStatusActive {
+		userStreams.Stop(account.ID)
+	}
+	logrus.WithFields(logrus.Fields{
+		"registered_account_id": account.ID.Hex(),
+		"status":                status,
+		"by":                    currentUserID(c),
+	}).Info("Registered account status updated")
+	c.JSON(200, utils.Success(gin.H{"status": "ok"}))
+}
+
+// DeleteAccountHandler godoc
+// @Summary Xóa tài khoản exchange của user
+// @Description Xóa tài khoản exchange khỏi user đang đăng nhập. Mặc định lịch sử giao dịch đã lưu được giữ lại,
+// @Description truyền purge=true để xóa cả fill, lịch sử order, income, nạp/rút, snapshot số dư và trạng thái đồng bộ của tài khoản
+// @Tags registered_accounts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Param purge query bool false "Xóa cả order đã lưu"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /accounts/{id} [delete]
+func DeleteAccountHandler(accountRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository, orderHistoryRepo *repositories.OrderHistoryRepository, incomeRepo *repositories.IncomeRepository, transferRepo *repositories.TransferRepository, snapshotRepo *repositories.BalanceSnapshotRepository, syncStateRepo *repositories.SyncStateRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService, secretStore secrets.SecretStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := requireAccountOwner(c, accountRepo, c.Param("id"))
+		if !ok {
+			return
+		}
+		purge, err := strconv.ParseBool(c.DefaultQuery("purge", "false"))
+		if err != nil {
+			c.JSON(400, utils.Error("Tham số purge không hợp lệ"))
+			return
+		}
+		if err := accountRepo.DeleteRegisteredAccount(c.Request.Context(), account.ID); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"error":                 err,
+			}).Error("Failed to delete registered account")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		clientManager.InvalidateClient(account.ID)
+		userStreams.Stop(account.ID)
+		if err := secretStore.Delete(c.Request.Context(), account); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"error":                 err,
+			}).Error("Failed to delete account secret")
+		}
+		if purge {
+			deleted, err := orderRepo.DeleteOrdersByAccountID(c.Request.Context(), account.ID)
+			if err == nil {
+				_, err = orderHistoryRepo.DeleteOrderHistoryByAccountID(c.Request.Context(), account.ID)
+			}
+			if err == nil {
+				_, err = incomeRepo.DeleteIncomesByAccountID(c.Request.Context(), account.ID)
+			}
+			if err == nil {
+				_, err = transferRepo.DeleteTransfersByAccountID(c.Request.Context(), account.ID)
+			}
+			if err == nil {
+				_, err = snapshotRepo.DeleteSnapshotsByAccountID(c.Request.Context(), account.ID)
+			}
+			if err == nil {
+				err = syncStateRepo.DeleteStates(c.Request.Context(), account.ID)
+			}
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to purge orders of deleted account")
+				c.JSON(500, utils.Error("Lỗi xóa lịch sử giao dịch"))
+				return
+			}
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"orders":                deleted,
+			}).Info("Purged orders of deleted account")
+		}
+		c.JSON(200, utils.Success(gin.H{"status": "ok"}))
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// adminAccount nạp tài khoản theo id trên path, trả về false và đã ghi response nếu lỗi
+func adminAccount(c *gin.Context, accountRepo *repositories.RegisteredAccountRepository) (models.RegisteredAccount, bool) {
+	id := c.Param("id")
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		c.JSON(400, utils.Error("ID tài khoản không hợp lệ"))
+		return models.RegisteredAccount{}, false
+	}
+	account, err := accountRepo.GetRegisteredAccount(id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(404, utils.Error("Không tìm thấy tài khoản"))
+			return models.RegisteredAccount{}, false
+		}
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": id,
+			"error":                 err,
+		}).Error("Failed to get registered account")
+		c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+		return models.RegisteredAccount{}, false
+	}
+	return account, true
+}
+
+// AdminListAccountsHandler godoc
+// @Summary Danh sách toàn bộ tài khoản exchange
+// @Description Dành cho operator/admin, không trả về API key
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.AdminAccountsResponse}
+// @Failure 401,403,500 {object} dto.APIResponse
+// @Router /admin/accounts [get]
+func AdminListAccountsHandler(accountRepo *repositories.RegisteredAccountRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accounts, err := accountRepo.GetAllRegisteredAccounts(c.Request.Context())
+		if err != nil {
+			logrus.WithField("error", err).Error("Failed to get registered accounts")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		data := make([]dto.AdminAccountResponse, 0, len(accounts))
+		for _, account := range accounts {
+			resp := dto.AdminAccountResponse{
+				ID:         account.ID.Hex(),
+				Username:   account.Username,
+				Exchange:   account.Exchange,
+				Market:     account.Market,
+				IsTestnet:  account.IsTestnet,
+				Status:     account.EffectiveStatus(),
+				ReferrerID: account.ReferrerID,
+			}
+			if !account.UserID.IsZero() {
+				resp.UserID = account.UserID.Hex()
+			}
+			data = append(data, resp)
+		}
+		c.JSON(200, utils.Success(dto.AdminAccountsResponse{Status: "ok", Data: data}))
+	}
+}
+
+// AdminSyncStatusHandler godoc
+// @Summary Trạng thái đồng bộ của tài khoản
+// @Description Trả về trạng thái đồng bộ của cả market, cursor theo từng symbol, mốc đồng bộ income (market futures_income)
+// @Description, cursor lịch sử order (market <market>_orders) và mốc đồng bộ nạp/rút (market transfers)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Success 200 {object} dto.APIResponse{data=dto.SyncStatusResponse}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/accounts/{id}/sync-status [get]
+func AdminSyncStatusHandler(accountRepo *repositories.RegisteredAccountRepository, syncStateRepo *repositories.SyncStateRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := adminAccount(c, accountRepo)
+		if !ok {
+			return
+		}
+		states, err := syncStateRepo.GetStates(c.Request.Context(), account.ID, account.Exchange, account.Market)
+		if err == nil {
+			var incomeStates []models.SyncState
+			incomeStates, err = syncStateRepo.GetStates(c.Request.Context(), account.ID, account.Exchange, services.IncomeSyncMarket)
+			states = append(states, incomeStates...)
+		}
+		if err == nil {
+			var orderStates []models.SyncState
+			orderStates, err = syncStateRepo.GetStates(c.Request.Context(), account.ID, account.Exchange, services.OrderHistorySyncMarket(account.Market))
+			states = append(states, orderStates...)
+		}
+		if err == nil {
+			var transferStates []models.SyncState
+			transferStates, err = syncStateRepo.GetStates(c.Request.Context(), account.ID, account.Exchange, services.TransferSyncMarket)
+			states = append(states, transferStates...)
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"error":                 err,
+			}).Error("Failed to get sync states")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.SyncStatusResponse{Status: "ok", Data: states}))
+	}
+}
+
+// AdminSyncAccountHandler godoc
+// @Summary Đồng bộ lịch sử giao dịch của một tài khoản
+// @Description Trigger đồng bộ trade, income, lịch sử order và nạp/rút (với market hỗ trợ) chạy nền, kết quả xem qua sync-status
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Success 202 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/accounts/{id}/sync [post]
+func AdminSyncAccountHandler(accountRepo *repositories.RegisteredAccountRepository, tradeHistoryService *services.TradeHistoryService, incomeService *services.IncomeService, orderHistoryService *services.OrderHistoryService, transferService *services.TransferService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := adminAccount(c, accountRepo)
+		if !ok {
+			return
+		}
+		go func() {
+			if err := tradeHistoryService.FetchAllTradeHistory(context.Background(), account); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to sync account triggered by operator")
+			}
+			err := incomeService.SyncIncome(context.Background(), account)
+			if err != nil && !errors.Is(err, exchanges.ErrIncomeNotSupported) {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to sync account income triggered by operator")
+			}
+			err = orderHistoryService.SyncOrderHistory(context.Background(), account)
+			if err != nil && !errors.Is(err, exchanges.ErrOrderHistoryNotSupported) {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to sync account order history triggered by operator")
+			}
+			err = transferService.SyncTransfers(context.Background(), account)
+			if err != nil && !errors.Is(err, exchanges.ErrTransfersNotSupported) {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to sync account transfers triggered by operator")
+			}
+		}()
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": account.ID.Hex(),
+			"operator_id":           currentUserID(c),
+		}).Info("Account sync triggered")
+		c.JSON(202, utils.Success(gin.H{"status": "ok"}))
+	}
+}
+
+// AdminUpdateAccountStatusHandler godoc
+// @Summary Đổi trạng thái đồng bộ của tài khoản
+// @Description Operator tắt (disabled) tài khoản có key lỗi hoặc bật lại (active)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Param body body dto.UpdateAccountStatusRequest true "Trạng thái mới (active, paused, disabled)"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/accounts/{id}/status [patch]
+func AdminUpdateAccountStatusHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := adminAccount(c, accountRepo)
+		if !ok {
+			return
+		}
+		var req dto.UpdateAccountStatusRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		switch req.Status {
+		case models.AccountStatusActive, models.AccountStatusPaused, models.AccountStatusDisabled:
+		default:
+			c.JSON(400, utils.Error("Trạng thái không hợp lệ"))
+			return
+		}
+		updateAccountStatus(c, accountRepo, clientManager, userStreams, account, req.Status)
+	}
+}
+
+// AdminSetUserRoleHandler godoc
+// @Summary Đổi vai trò của user
+// @Description Chỉ admin, role mới có hiệu lực từ lần đăng nhập tiếp theo
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID user"
+// @Param body body dto.SetRoleRequest true "Vai trò mới (user, operator, admin)"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/users/{id}/role [put]
+func AdminSetUserRoleHandler(userService *services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(400, utils.Error("ID user không hợp lệ"))
+			return
+		}
+		var req dto.SetRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !models.IsValidRole(req.Role) {
+			c.JSON(400, utils.Error("Role không hợp lệ"))
+			return
+		}
+		if err := userService.SetRole(c.Request.Context(), id, req.Role); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				c.JSON(404, utils.Error("Không tìm thấy user"))
+				return
+			}
+			logrus.WithField("error", err).Error("Failed to update user role")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		logrus.WithFields(logrus.Fields{
+			"user_id":  id.Hex(),
+			"role":     req.Role,
+			"admin_id": currentUserID(c),
+		}).Info("User role updated")
+		c.JSON(200, utils.Success(gin.H{"status": "ok"}))
+	}
+}
+
+// FetchAllTradesForUser godoc
+// @Summary Lấy toàn bộ lịch sử giao dịch của các tài khoản đã đăng ký
+// @Description Trigger lấy lịch sử giao dịch cho tất cả registered_accounts
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.FetchAllTradesResponse}
+// @Failure 401,403,500 {object} dto.APIResponse
+// @Router /admin/fetch-trades-all-user [post]
+func FetchAllTradesForUser(tradeHistoryService *services.TradeHistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := tradeHistoryService.FetchAllAccountTradeHistory(c.Request.Context())
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err,
+			}).Error("Failed to fetch trades of users")
+			c.JSON(500, utils.Error("Lỗi lấy danh sách lệnh"))
+			return
+		}
+		resp := dto.FetchAllTradesResponse{Status: "ok"}
+		c.JSON(200, utils.Success(resp))
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetBalanceSnapshotsHandler godoc
+// @Summary Snapshot số dư của tài khoản
+// @Description Lấy các snapshot số dư spot hoặc số dư ví/margin futures theo asset trong khoảng thời gian
+// @Tags balances
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetBalancesRequest true "Tài khoản và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=dto.BalanceSnapshotsResponse}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /balances [post]
+func GetBalanceSnapshotsHandler(accountRepo *repositories.RegisteredAccountRepository, balanceService *services.BalanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetBalancesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		snapshots, err := balanceService.GetSnapshots(c.Request.Context(), account, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get balance snapshots")
+			c.JSON(500, utils.Error("Lỗi lấy snapshot số dư"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.BalanceSnapshotsResponse{Status: "ok", Data: snapshots}))
+	}
+}
+
+// GetDailyEquityHandler godoc
+// @Summary Equity theo ngày của tài khoản
+// @Description Equity quy đổi sang USDT theo snapshot cuối cùng của mỗi ngày (UTC) trong khoảng thời gian
+// @Tags balances
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetBalancesRequest true "Tài khoản và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=models.EquityCurve}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /balances/equity [post]
+func GetDailyEquityHandler(accountRepo *repositories.RegisteredAccountRepository, balanceService *services.BalanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetBalancesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		curve, err := balanceService.DailyEquity(c.Request.Context(), account, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to compute daily equity")
+			c.JSON(500, utils.Error("Lỗi tính equity"))
+			return
+		}
+		c.JSON(200, utils.Success(curve))
+	}
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "autobackcom/internal/models"
+
+// RegisteredAccountResponse là thông tin tài khoản exchange trả về cho user, không gồm API key
+type RegisteredAccountResponse struct {
+	ID          string                    `json:"id"`
+	Exchange    string                    `json:"exchange"`
+	Market      string                    `json:"market"`
+	IsTestnet   bool                      `json:"isTestnet"`
+	Status      string                    `json:"status"`
+	ReferrerID  string                    `json:"referrerID,omitempty"`
+	Permissions *models.APIKeyPermissions `json:"permissions,omitempty"`
+}
+
+type AccountsResponse struct {
+	Status string                      `json:"status"`
+	Data   []RegisteredAccountResponse `json:"data"`
+}
+
+// UpdateAccountKeysRequest thay API key/secret (và passphrase với OKX) của tài khoản đã đăng ký
+type UpdateAccountKeysRequest struct {
+	APIKey     string `json:"apikey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// UpdateAccountStatusRequest đổi trạng thái đồng bộ (active, paused, disabled)
+type UpdateAccountStatusRequest struct {
+	Status string `json:"status"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// AdminAccountResponse là thông tin tài khoản exchange hiển thị cho operator
+type AdminAccountResponse struct {
+	ID         string `json:"id"`
+	UserID     string `json:"userID"`
+	Username   string `json:"username"`
+	Exchange   string `json:"exchange"`
+	Market     string `json:"market"`
+	IsTestnet  bool   `json:"isTestnet"`
+	Status     string `json:"status"`
+	ReferrerID string `json:"referrerID,omitempty"`
+}
+
+type AdminAccountsResponse struct {
+	Status string                 `json:"status"`
+	Data   []AdminAccountResponse `json:"data"`
+}
+
+type SyncStatusResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
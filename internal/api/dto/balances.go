@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type GetBalancesRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+}
+
+type BalanceSnapshotsResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
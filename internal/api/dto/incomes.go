@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type GetIncomesRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	IncomeType          string    `json:"incomeType,omitempty"` // Rỗng là mọi loại
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+}
+
+type IncomesResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type GetOrdersRequest struct {
 	RegisteredAccountID string `json:"registeredAccountID"`
 }
@@ -8,3 +10,15 @@ type GetOrdersResponse struct {
 	Status string      `json:"status"`
 	Data   interface{} `json:"data"`
 }
+
+// GetOrderHistoryRequest lọc lịch sử order theo tài khoản, symbol, trạng thái (FILLED, CANCELED, ...)
+// và khoảng thời gian tạo order, kết quả được phân trang theo page/limit
+type GetOrderHistoryRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	Symbol              string    `json:"symbol"`
+	Status              string    `json:"status"`
+	From                time.Time `json:"from"`  // Bỏ trống là không giới hạn
+	To                  time.Time `json:"to"`    // Bỏ trống là tới hiện tại
+	Page                int64     `json:"page"`  // Bắt đầu từ 1, mặc định 1
+	Limit               int64     `json:"limit"` // Mặc định 100, tối đa 500
+}
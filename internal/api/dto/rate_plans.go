@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"autobackcom/internal/models"
+	"time"
+)
+
+type RatePlanRequest struct {
+	Name       string            `json:"name"`
+	Exchange   string            `json:"exchange"`
+	ReferrerID string            `json:"referrerID"`
+	Tiers      []models.RateTier `json:"tiers"`
+}
+
+type AssignRatePlanRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	EffectiveFrom       time.Time `json:"effectiveFrom"`
+}
+
+type RatePlansResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type CalculateRebateRequest struct {
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+}
+
+type GetFeesRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+}
+
+type GetRebatesRequest struct {
+	RegisteredAccountID string `json:"registeredAccountID"`
+}
+
+type RebateStatementsResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
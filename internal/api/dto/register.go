@@ -1,25 +1,13 @@
 package dto
 
-type ExchangeType string
-
-const (
-	ExchangeBinance ExchangeType = "binance"
-)
-
-type MarketType string
-
-const (
-	MarketSpot    MarketType = "spot"
-	MarketFutures MarketType = "futures"
-)
-
 type RegisterRequest struct {
-	Username  string       `json:"username"`
-	Exchange  ExchangeType `json:"exchange"`
-	Market    MarketType   `json:"market"`
-	APIKey    string       `json:"apikey"`
-	Secret    string       `json:"secret"`
-	IsTestnet bool         `json:"isTestnet"`
+	Exchange   string `json:"exchange"`
+	Market     string `json:"market"`
+	APIKey     string `json:"apikey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase,omitempty"` // Bắt buộc với exchange cần passphrase (OKX)
+	IsTestnet  bool   `json:"isTestnet"`
+	ReferrerID string `json:"referrerID"`
 }
 
 type RegisterResponse struct {
@@ -27,34 +15,41 @@ type RegisterResponse struct {
 	Status              string `json:"status"`
 }
 
-// Validate ExchangeType
-func (e ExchangeType) IsValid() bool {
-	switch e {
-	case ExchangeBinance:
-		return true
-	default:
-		return false
-	}
+type SignupRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// APIKeyLoginRequest đăng nhập bằng API key/secret của một tài khoản đã đăng ký
+type APIKeyLoginRequest struct {
+	RegisteredAccountID string `json:"registeredAccountID"`
+	APIKey              string `json:"apikey"`
+	Secret              string `json:"secret"`
 }
 
-// Chuyển đổi từ string sang ExchangeType
-func ToExchangeType(s string) (ExchangeType, bool) {
-	et := ExchangeType(s)
-	return et, et.IsValid()
+type LoginResponse struct {
+	Token string `json:"token"`
 }
 
-// Validate MarketType
-func (m MarketType) IsValid() bool {
-	switch m {
-	case MarketSpot, MarketFutures:
-		return true
-	default:
-		return false
-	}
+// MarketResponse mô tả một market mà adapter của exchange hỗ trợ
+type MarketResponse struct {
+	Market          string `json:"market"`
+	SupportsTestnet bool   `json:"supportsTestnet"`
+	Income          bool   `json:"income"`       // Có đồng bộ lịch sử income hay không
+	OrderHistory    bool   `json:"orderHistory"` // Có đồng bộ lịch sử order (kể cả order bị hủy) hay không
+	UserStream      bool   `json:"userStream"`   // Có nhận fill theo thời gian thực qua user data stream hay không
+	Transfers       bool   `json:"transfers"`    // Có đồng bộ lịch sử nạp/rút/chuyển tiền (chỉ mainnet) hay không
+	Balances        bool   `json:"balances"`     // Có snapshot số dư định kỳ hay không
 }
 
-// Chuyển đổi từ string sang MarketType
-func ToMarketType(s string) (MarketType, bool) {
-	mt := MarketType(s)
-	return mt, mt.IsValid()
+// ExchangeResponse mô tả một exchange có thể đăng ký tài khoản
+type ExchangeResponse struct {
+	Exchange           string           `json:"exchange"`
+	RequiresPassphrase bool             `json:"requiresPassphrase"`
+	Markets            []MarketResponse `json:"markets"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type GetTransfersRequest struct {
+	RegisteredAccountID string    `json:"registeredAccountID"`
+	Type                string    `json:"type,omitempty"` // DEPOSIT, WITHDRAWAL, TRANSFER, rỗng là mọi loại
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+}
+
+type TransfersResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
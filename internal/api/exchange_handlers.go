@@ -0,0 +1,43 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListExchangesHandler godoc
+// @Summary Danh sách exchange hỗ trợ
+// @Description Các exchange/market có thể đăng ký tài khoản, kèm yêu cầu passphrase, hỗ trợ testnet, đồng bộ income, lịch sử order, nạp/rút, snapshot số dư và user data stream
+// @Tags exchanges
+// @Produce json
+// @Success 200 {object} dto.APIResponse{data=[]dto.ExchangeResponse}
+// @Router /exchanges [get]
+func ListExchangesHandler(registry *exchanges.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adapters := registry.Adapters()
+		response := make([]dto.ExchangeResponse, 0, len(adapters))
+		for _, adapter := range adapters {
+			markets := make([]dto.MarketResponse, 0, len(adapter.Markets))
+			for _, market := range adapter.Markets {
+				markets = append(markets, dto.MarketResponse{
+					Market:          market.Name,
+					SupportsTestnet: market.SupportsTestnet,
+					Income:          market.NewIncomeFetcher != nil,
+					OrderHistory:    market.NewOrderHistoryFetcher != nil,
+					UserStream:      market.NewUserStreamer != nil,
+					Transfers:       market.NewTransferFetcher != nil,
+					Balances:        market.NewBalanceFetcher != nil,
+				})
+			}
+			response = append(response, dto.ExchangeResponse{
+				Exchange:           adapter.Exchange,
+				RequiresPassphrase: adapter.RequiresPassphrase,
+				Markets:            markets,
+			})
+		}
+		c.JSON(200, utils.Success(response))
+	}
+}
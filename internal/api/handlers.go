@@ -2,11 +2,15 @@ package api
 
 import (
 	"autobackcom/internal/api/dto"
+	"autobackcom/internal/exchanges"
 	"autobackcom/internal/models"
 	"autobackcom/internal/repositories"
+	"autobackcom/internal/secrets"
 	"autobackcom/internal/services"
 	"autobackcom/internal/utils"
 	"context"
+	"crypto/subtle"
+	"errors"
 	"os"
 	"strings"
 	"time"
@@ -15,14 +19,35 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
+// jwtSecret chỉ được ghi một lần bởi LoadJWTSecret trước khi server nhận request
+var jwtSecret []byte
 
-func GenerateToken(userID string) (string, error) {
-	claims := &jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+// LoadJWTSecret đọc JWT_SECRET khi khởi động (sau khi main đã load .env), main dừng ngay nếu thiếu
+func LoadJWTSecret() error {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return errors.New("JWT_SECRET environment variable is not set")
+	}
+	jwtSecret = []byte(secret)
+	return nil
+}
+
+// Claims là JWT claims của hệ thống, subject là ID của user
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func GenerateToken(userID, role string) (string, error) {
+	claims := &Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
@@ -38,16 +63,16 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
+		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 			return jwtSecret, nil
-		})
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 		if err != nil || !token.Valid {
 			logrus.WithField("error", err).Error("Invalid token")
 			c.JSON(401, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
-		claims, ok := token.Claims.(*jwt.RegisteredClaims)
+		claims, ok := token.Claims.(*Claims)
 		if !ok {
 			logrus.Error("Invalid token claims")
 			c.JSON(401, gin.H{"error": "Invalid token claims"})
@@ -55,21 +80,244 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		c.Set("userID", claims.Subject)
+		c.Set("role", claims.Role)
 		c.Next()
 	}
 }
 
+// RequireRole chỉ cho phép request có role thuộc danh sách, dùng sau JWTAuthMiddleware
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		logrus.WithFields(logrus.Fields{
+			"user_id": currentUserID(c),
+			"role":    role,
+			"path":    c.FullPath(),
+		}).Error("Access denied for role")
+		c.JSON(403, utils.Error("Không có quyền truy cập"))
+		c.Abort()
+	}
+}
+
+// currentUserID trả về subject của token đã được JWTAuthMiddleware xác thực
+func currentUserID(c *gin.Context) string {
+	return c.GetString("userID")
+}
+
+// currentUserObjectID trả về ID của user đang đăng nhập
+func currentUserObjectID(c *gin.Context) (primitive.ObjectID, error) {
+	return primitive.ObjectIDFromHex(currentUserID(c))
+}
+
+// requireAccountOwner nạp tài khoản được yêu cầu và kiểm tra tài khoản thuộc về user của token,
+// trả về false và đã ghi response lỗi nếu ID không hợp lệ, không tìm thấy hoặc không phải chủ sở hữu
+func requireAccountOwner(c *gin.Context, accountRepo *repositories.RegisteredAccountRepository, registeredAccountID string) (models.RegisteredAccount, bool) {
+	if _, err := primitive.ObjectIDFromHex(registeredAccountID); err != nil {
+		logrus.WithField("error", err).Error("Invalid registered account ID")
+		c.JSON(400, utils.Error("ID tài khoản không hợp lệ"))
+		return models.RegisteredAccount{}, false
+	}
+	account, err := accountRepo.GetRegisteredAccount(registeredAccountID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(404, utils.Error("Không tìm thấy tài khoản"))
+			return models.RegisteredAccount{}, false
+		}
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": registeredAccountID,
+			"error":                 err,
+		}).Error("Failed to get registered account")
+		c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+		return models.RegisteredAccount{}, false
+	}
+	if account.UserID.IsZero() || account.UserID.Hex() != currentUserID(c) {
+		logrus.WithFields(logrus.Fields{
+			"user_id":               currentUserID(c),
+			"registered_account_id": registeredAccountID,
+		}).Error("Access to registered account denied")
+		c.JSON(403, utils.Error("Không có quyền truy cập tài khoản này"))
+		return models.RegisteredAccount{}, false
+	}
+	return account, true
+}
+
+// writeKeyValidationError trả lỗi phù hợp khi kiểm tra API key với exchange thất bại
+func writeKeyValidationError(c *gin.Context, username string, err error) {
+	logrus.WithFields(logrus.Fields{
+		"user":  username,
+		"error": err,
+	}).Error("API key validation failed")
+	switch {
+	case errors.Is(err, exchanges.ErrInvalidAPIKey):
+		c.JSON(400, utils.Error("API key hoặc secret không hợp lệ"))
+	case errors.Is(err, exchanges.ErrUnsupportedMarket):
+		c.JSON(400, utils.Error("Exchange không hỗ trợ market này"))
+	case errors.Is(err, services.ErrAPIKeyNotReadOnly):
+		c.JSON(422, utils.Error("API key chỉ được có quyền đọc, hãy tắt quyền giao dịch, rút tiền và mọi quyền chuyển tiền (nội bộ, universal transfer)"))
+	default:
+		c.JSON(502, utils.Error("Không kiểm tra được API key với exchange"))
+	}
+}
+
+// SignupHandler godoc
+// @Summary Tạo người dùng mới
+// @Description Tạo user bằng username/mật khẩu và cấp JWT
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body dto.SignupRequest true "Thông tin người dùng"
+// @Success 201 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 400,409,500 {object} dto.APIResponse
+// @Router /signup [post]
+func SignupHandler(userService *services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.SignupRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.Username == "" {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		user, err := userService.Signup(c.Request.Context(), req.Username, req.Password)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrWeakPassword):
+				c.JSON(400, utils.Error("Mật khẩu phải có ít nhất 8 ký tự"))
+			case errors.Is(err, services.ErrUsernameTaken):
+				c.JSON(409, utils.Error("Username đã tồn tại"))
+			default:
+				logrus.WithFields(logrus.Fields{
+					"username": req.Username,
+					"error":    err,
+				}).Error("Failed to create user")
+				c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			}
+			return
+		}
+		token, err := GenerateToken(user.ID.Hex(), user.EffectiveRole())
+		if err != nil {
+			logrus.WithField("error", err).Error("Failed to generate token")
+			c.JSON(500, utils.Error("Lỗi tạo token"))
+			return
+		}
+		c.JSON(201, utils.Success(dto.LoginResponse{Token: token}))
+		logrus.WithField("user", user.Username).Info("User signed up successfully")
+	}
+}
+
+// LoginHandler godoc
+// @Summary Đăng nhập bằng username/mật khẩu
+// @Description Xác thực user và cấp JWT
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body dto.LoginRequest true "Thông tin đăng nhập"
+// @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 400,401,500 {object} dto.APIResponse
+// @Router /login [post]
+func LoginHandler(userService *services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.LoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		user, err := userService.Authenticate(c.Request.Context(), req.Username, req.Password)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidCredentials) {
+				logrus.WithField("username", req.Username).Error("Login failed: invalid credentials")
+				c.JSON(401, utils.Error("Thông tin đăng nhập không đúng"))
+				return
+			}
+			logrus.WithField("error", err).Error("Login failed")
+			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+			return
+		}
+		token, err := GenerateToken(user.ID.Hex(), user.EffectiveRole())
+		if err != nil {
+			logrus.WithField("error", err).Error("Failed to generate token")
+			c.JSON(500, utils.Error("Lỗi tạo token"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.LoginResponse{Token: token}))
+	}
+}
+
+// APIKeyLoginHandler godoc
+// @Summary Đăng nhập bằng API key của tài khoản đã đăng ký
+// @Description Xác thực bằng API key/secret đã đăng ký và cấp JWT cho user sở hữu tài khoản
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body dto.APIKeyLoginRequest true "Thông tin đăng nhập"
+// @Success 200 {object} dto.APIResponse{data=dto.LoginResponse}
+// @Failure 400,401,500 {object} dto.APIResponse
+// @Router /login/apikey [post]
+func APIKeyLoginHandler(userRepo *repositories.RegisteredAccountRepository, userService *services.UserService, secretStore secrets.SecretStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.APIKeyLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, err := userRepo.GetRegisteredAccount(req.RegisteredAccountID)
+		if err != nil {
+			logrus.WithField("registered_account_id", req.RegisteredAccountID).Error("Login failed: account not found")
+			c.JSON(401, utils.Error("Thông tin đăng nhập không đúng"))
+			return
+		}
+		creds, err := secretStore.Open(c.Request.Context(), account)
+		if err != nil {
+			logrus.WithField("error", err).Error("Decryption error")
+			c.JSON(500, utils.Error("Lỗi giải mã"))
+			return
+		}
+		keyMatch := subtle.ConstantTimeCompare([]byte(creds.APIKey), []byte(req.APIKey)) == 1
+		secretMatch := subtle.ConstantTimeCompare([]byte(creds.Secret), []byte(req.Secret)) == 1
+		if !keyMatch || !secretMatch || account.UserID.IsZero() {
+			logrus.WithField("registered_account_id", req.RegisteredAccountID).Error("Login failed: credentials mismatch")
+			c.JSON(401, utils.Error("Thông tin đăng nhập không đúng"))
+			return
+		}
+		user, err := userService.GetUser(c.Request.Context(), account.UserID)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Login failed: owner user not found")
+			c.JSON(401, utils.Error("Thông tin đăng nhập không đúng"))
+			return
+		}
+		token, err := GenerateToken(user.ID.Hex(), user.EffectiveRole())
+		if err != nil {
+			logrus.WithField("error", err).Error("Failed to generate token")
+			c.JSON(500, utils.Error("Lỗi tạo token"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.LoginResponse{Token: token}))
+	}
+}
+
 // RegisterHandler godoc
 // @Summary Đăng ký tài khoản giao dịch
-// @Description Đăng ký tài khoản để lấy lịch sử giao dịch
+// @Description Thêm tài khoản exchange cho user đang đăng nhập để lấy lịch sử giao dịch. API key phải hợp lệ và chỉ có quyền đọc
+// @Description (không bật giao dịch, rút tiền hay chuyển tiền nội bộ). Exchange/market hỗ trợ và yêu cầu passphrase xem tại GET /exchanges
 // @Tags registered_accounts
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param body body dto.RegisterRequest true "Thông tin đăng ký"
 // @Success 201 {object} dto.APIResponse{data=dto.RegisterResponse}
-// @Failure 400,500 {object} dto.APIResponse
+// @Failure 400,401,422,500,502 {object} dto.APIResponse
 // @Router /register [post]
-func RegisterHandler(userRepo *repositories.RegisteredAccountRepository, tradeHistoryService *services.TradeHistoryService) gin.HandlerFunc {
+func RegisterHandler(userRepo *repositories.RegisteredAccountRepository, userService *services.UserService, clientManager *services.ClientManagerService, secretStore secrets.SecretStore, tradeHistoryService *services.TradeHistoryService, registry *exchanges.Registry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req dto.RegisterRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -77,38 +325,65 @@ func RegisterHandler(userRepo *repositories.RegisteredAccountRepository, tradeHi
 			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
 			return
 		}
-		// Validate Exchange and Market
-		if !req.Exchange.IsValid() {
+		// Validate Exchange and Market theo adapter đã đăng ký
+		adapter, ok := registry.Adapter(req.Exchange)
+		if !ok {
 			logrus.WithField("exchange", req.Exchange).Error("Invalid exchange type")
 			c.JSON(400, utils.Error("Exchange không hợp lệ"))
 			return
 		}
-		if !req.Market.IsValid() {
+		market, ok := adapter.Market(req.Market)
+		if !ok {
 			logrus.WithField("market", req.Market).Error("Invalid market type")
 			c.JSON(400, utils.Error("Market không hợp lệ"))
 			return
 		}
-
-		encryptedAPIKey, err := utils.Encrypt(req.APIKey)
+		if adapter.RequiresPassphrase && req.Passphrase == "" {
+			c.JSON(400, utils.Error("Thiếu passphrase của API key"))
+			return
+		}
+		if req.IsTestnet && !market.SupportsTestnet {
+			logrus.WithField("market", req.Market).Error("Market is not available on testnet")
+			c.JSON(400, utils.Error("Market không hỗ trợ testnet"))
+			return
+		}
+		userID, err := currentUserObjectID(c)
 		if err != nil {
-			logrus.WithField("error", err).Error("Encryption error")
-			c.JSON(500, utils.Error("Lỗi mã hóa"))
+			c.JSON(401, utils.Error("Invalid token"))
 			return
 		}
-		encryptedSecret, err := utils.Encrypt(req.Secret)
+		user, err := userService.GetUser(c.Request.Context(), userID)
 		if err != nil {
-			logrus.WithField("error", err).Error("Encryption error")
-			c.JSON(500, utils.Error("Lỗi mã hóa"))
+			logrus.WithFields(logrus.Fields{
+				"user_id": userID.Hex(),
+				"error":   err,
+			}).Error("Failed to get user")
+			c.JSON(401, utils.Error("Không tìm thấy người dùng"))
+			return
+		}
+
+		// Kiểm tra key với exchange trước khi lưu
+		creds := secrets.Credentials{APIKey: req.APIKey, Secret: req.Secret, Passphrase: req.Passphrase}
+		permissions, err := clientManager.ValidateKey(c.Request.Context(), req.Exchange, req.Market, creds, req.IsTestnet)
+		if err != nil {
+			writeKeyValidationError(c, user.Username, err)
 			return
 		}
+
 		account := models.RegisteredAccount{
-			ID:              primitive.NewObjectID(),
-			Username:        req.Username,
-			Exchange:        string(req.Exchange),
-			Market:          string(req.Market),
-			EncryptedAPIKey: encryptedAPIKey,
-			EncryptedSecret: encryptedSecret,
-			IsTestnet:       req.IsTestnet,
+			ID:          primitive.NewObjectID(),
+			UserID:      user.ID,
+			Username:    user.Username,
+			Exchange:    req.Exchange,
+			Market:      req.Market,
+			IsTestnet:   req.IsTestnet,
+			ReferrerID:  req.ReferrerID,
+			Permissions: &permissions,
+		}
+		if err := secretStore.Seal(c.Request.Context(), &account, creds); err != nil {
+			logrus.WithField("error", err).Error("Encryption error")
+			c.JSON(500, utils.Error("Lỗi mã hóa"))
+			return
 		}
 		err = userRepo.SaveRegisteredAccount(account)
 		if err != nil {
@@ -116,6 +391,13 @@ func RegisterHandler(userRepo *repositories.RegisteredAccountRepository, tradeHi
 				"user":  account.Username,
 				"error": err,
 			}).Error("Failed to save user")
+			// Tài khoản không được lưu nên secret ở backend ngoài (Vault) không còn ai tham chiếu
+			if err := secretStore.Delete(c.Request.Context(), account); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"registered_account_id": account.ID.Hex(),
+					"error":                 err,
+				}).Error("Failed to delete orphaned secret")
+			}
 			c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
 			return
 		}
@@ -135,40 +417,90 @@ func RegisterHandler(userRepo *repositories.RegisteredAccountRepository, tradeHi
 	}
 }
 
-// FetchAllTradesForUser godoc
-// @Summary Lấy toàn bộ lịch sử giao dịch của các tài khoản đã đăng ký
-// @Description Trigger lấy lịch sử giao dịch cho tất cả registered_accounts
-// @Tags trades
+// GetOrdersHandler godoc
+// @Summary Lấy danh sách lệnh của tài khoản
+// @Description Lấy danh sách fill (mỗi lần khớp là một bản ghi) theo registered_account_id, bỏ trống để lấy của mọi tài khoản của user
+// @Tags orders
+// @Accept json
 // @Produce json
-// @Success 200 {object} dto.APIResponse{data=dto.FetchAllTradesResponse}
-// @Failure 500 {object} dto.APIResponse
-// @Router /fetch-trades-all-user [post]
-func FetchAllTradesForUser(tradeHistoryService *services.TradeHistoryService) gin.HandlerFunc {
+// @Security BearerAuth
+// @Param body body dto.GetOrdersRequest true "ID tài khoản đã đăng ký"
+// @Success 200 {object} dto.APIResponse{data=dto.GetOrdersResponse}
+// @Failure 400,401,403,500 {object} dto.APIResponse
+// @Router /orders [post]
+func GetOrdersHandler(userRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		err := tradeHistoryService.FetchAllAccountTradeHistory(c.Request.Context())
+		var req dto.GetOrdersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		accountIDs, ok := requestAccountIDs(c, userRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		orders, err := orderRepo.GetOrdersByAccountIDs(accountIDs)
 		if err != nil {
 			logrus.WithFields(logrus.Fields{
-				"error": err,
-			}).Error("Failed to fetch trades of users")
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get orders")
 			c.JSON(500, utils.Error("Lỗi lấy danh sách lệnh"))
 			return
 		}
-		resp := dto.FetchAllTradesResponse{Status: "ok"}
+		resp := dto.GetOrdersResponse{
+			Status: "ok",
+			Data:   orders,
+		}
 		c.JSON(200, utils.Success(resp))
 	}
 }
 
-// GetOrdersHandler godoc
-// @Summary Lấy danh sách lệnh của tài khoản
-// @Description Lấy danh sách order theo registered_account_id
+// requestAccountIDs trả về tài khoản được yêu cầu (phải thuộc user) hoặc mọi tài khoản của user nếu
+// bỏ trống, trả về false và đã ghi response nếu lỗi
+func requestAccountIDs(c *gin.Context, userRepo *repositories.RegisteredAccountRepository, registeredAccountID string) ([]primitive.ObjectID, bool) {
+	if registeredAccountID != "" {
+		account, ok := requireAccountOwner(c, userRepo, registeredAccountID)
+		if !ok {
+			return nil, false
+		}
+		return []primitive.ObjectID{account.ID}, true
+	}
+	userID, err := currentUserObjectID(c)
+	if err != nil {
+		c.JSON(401, utils.Error("Invalid token"))
+		return nil, false
+	}
+	accounts, err := userRepo.GetRegisteredAccountsByUserID(c.Request.Context(), userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"user_id": userID.Hex(),
+			"error":   err,
+		}).Error("Failed to get registered accounts")
+		c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+		return nil, false
+	}
+	accountIDs := make([]primitive.ObjectID, 0, len(accounts))
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, account.ID)
+	}
+	return accountIDs, true
+}
+
+// GetAggregatedOrdersHandler godoc
+// @Summary Lấy danh sách order gộp từ các fill
+// @Description Gộp các fill đã lưu theo order: tổng khối lượng, giá khớp trung bình, phí theo từng asset và số fill.
+// @Description Bỏ trống registeredAccountID để lấy order của mọi tài khoản của user
 // @Tags orders
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param body body dto.GetOrdersRequest true "ID tài khoản đã đăng ký"
-// @Success 200 {object} dto.APIResponse{data=dto.GetOrdersResponse}
-// @Failure 400,500 {object} dto.APIResponse
-// @Router /orders [post]
-func GetOrdersHandler(userRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository) gin.HandlerFunc {
+// @Success 200 {object} dto.APIResponse{data=[]models.AggregatedOrder}
+// @Failure 400,401,403,500 {object} dto.APIResponse
+// @Router /orders/aggregated [post]
+func GetAggregatedOrdersHandler(userRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req dto.GetOrdersRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -176,25 +508,86 @@ func GetOrdersHandler(userRepo *repositories.RegisteredAccountRepository, orderR
 			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
 			return
 		}
-		id, err := primitive.ObjectIDFromHex(req.RegisteredAccountID)
-		if err != nil {
-			logrus.WithField("error", err).Error("Invalid registered account ID")
-			c.JSON(400, utils.Error("ID tài khoản không hợp lệ"))
+		accountIDs, ok := requestAccountIDs(c, userRepo, req.RegisteredAccountID)
+		if !ok {
 			return
 		}
-		orders, err := orderRepo.GetOrdersByUserID(id)
+		orders, err := orderRepo.GetAggregatedOrders(c.Request.Context(), accountIDs)
 		if err != nil {
 			logrus.WithFields(logrus.Fields{
 				"registered_account_id": req.RegisteredAccountID,
 				"error":                 err,
-			}).Error("Failed to get orders")
+			}).Error("Failed to aggregate orders")
 			c.JSON(500, utils.Error("Lỗi lấy danh sách lệnh"))
 			return
 		}
-		resp := dto.GetOrdersResponse{
-			Status: "ok",
-			Data:   orders,
+		c.JSON(200, utils.Success(orders))
+	}
+}
+
+const (
+	// Số order mặc định và tối đa của một trang lịch sử order
+	defaultOrderHistoryLimit = 100
+	maxOrderHistoryLimit     = 500
+)
+
+// GetOrderHistoryHandler godoc
+// @Summary Lấy lịch sử order kèm fill
+// @Description Lấy vòng đời order (kể cả order bị hủy/hết hạn không có fill) kèm các fill của từng order.
+// @Description Bỏ trống registeredAccountID để lấy của mọi tài khoản của user; symbol, status và from/to để lọc.
+// @Description Kết quả sắp order mới nhất trước, phân trang theo page (từ 1) và limit (mặc định 100, tối đa 500).
+// @Description Order chỉ được đồng bộ cho symbol đã có fill, symbol có số dư/vị thế/income hoặc đang có order mở,
+// @Description nên order bị hủy/hết hạn trên symbol chưa từng có các dấu hiệu đó sẽ không có trong kết quả.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetOrderHistoryRequest true "Tài khoản và bộ lọc"
+// @Success 200 {object} dto.APIResponse{data=[]models.OrderHistoryWithFills}
+// @Failure 400,401,403,500 {object} dto.APIResponse
+// @Router /orders/history [post]
+func GetOrderHistoryHandler(userRepo *repositories.RegisteredAccountRepository, orderHistoryService *services.OrderHistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetOrderHistoryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
 		}
-		c.JSON(200, utils.Success(resp))
+		if req.Page == 0 {
+			req.Page = 1
+		}
+		if req.Limit == 0 {
+			req.Limit = defaultOrderHistoryLimit
+		}
+		if req.Page < 0 || req.Limit < 0 || req.Limit > maxOrderHistoryLimit {
+			c.JSON(400, utils.Error("Tham số phân trang không hợp lệ"))
+			return
+		}
+		if !req.From.IsZero() && !req.To.IsZero() && !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		accountIDs, ok := requestAccountIDs(c, userRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		orders, err := orderHistoryService.GetOrderHistory(c.Request.Context(), accountIDs, repositories.OrderHistoryFilter{
+			Symbol: req.Symbol,
+			Status: req.Status,
+			From:   req.From,
+			To:     req.To,
+			Skip:   (req.Page - 1) * req.Limit,
+			Limit:  req.Limit,
+		})
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get order history")
+			c.JSON(500, utils.Error("Lỗi lấy lịch sử lệnh"))
+			return
+		}
+		c.JSON(200, utils.Success(orders))
 	}
 }
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetIncomesHandler godoc
+// @Summary Lịch sử income futures của tài khoản
+// @Description Lấy các bản ghi income (REALIZED_PNL, FUNDING_FEE, COMMISSION, ...) trong khoảng thời gian
+// @Tags incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetIncomesRequest true "Tài khoản, loại income và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=dto.IncomesResponse}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /incomes [post]
+func GetIncomesHandler(accountRepo *repositories.RegisteredAccountRepository, incomeService *services.IncomeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetIncomesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		incomes, err := incomeService.GetIncomes(c.Request.Context(), account, req.IncomeType, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get incomes")
+			c.JSON(500, utils.Error("Lỗi lấy lịch sử income"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.IncomesResponse{Status: "ok", Data: incomes}))
+	}
+}
+
+// GetIncomeSummaryHandler godoc
+// @Summary Tổng hợp income futures của tài khoản
+// @Description Tổng PnL đã chốt, funding, phí và hoàn phí theo loại income và asset trong khoảng thời gian
+// @Tags incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetIncomesRequest true "Tài khoản và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=models.IncomeSummary}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /incomes/summary [post]
+func GetIncomeSummaryHandler(accountRepo *repositories.RegisteredAccountRepository, incomeService *services.IncomeService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetIncomesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		summary, err := incomeService.SummarizeIncomes(c.Request.Context(), account, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to summarize incomes")
+			c.JSON(500, utils.Error("Lỗi tổng hợp income"))
+			return
+		}
+		c.JSON(200, utils.Success(summary))
+	}
+}
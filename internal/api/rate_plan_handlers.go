@@ -0,0 +1,213 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/models"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ratePlanID đọc id gói hoàn phí từ path, trả về false và đã ghi response nếu không hợp lệ
+func ratePlanID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		logrus.WithField("error", err).Error("Invalid rate plan ID")
+		c.JSON(400, utils.Error("ID gói hoàn phí không hợp lệ"))
+		return id, false
+	}
+	return id, true
+}
+
+// writeRatePlanError trả lỗi phù hợp cho các thao tác với gói hoàn phí
+func writeRatePlanError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRatePlan):
+		c.JSON(400, utils.Error(err.Error()))
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.JSON(404, utils.Error("Không tìm thấy dữ liệu"))
+	default:
+		logrus.WithField("error", err).Error(msg)
+		c.JSON(500, utils.Error("Lỗi cơ sở dữ liệu"))
+	}
+}
+
+// ListRatePlansHandler godoc
+// @Summary Lấy danh sách gói hoàn phí
+// @Tags rate_plans
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIResponse{data=dto.RatePlansResponse}
+// @Failure 401,403,500 {object} dto.APIResponse
+// @Router /admin/rate-plans [get]
+func ListRatePlansHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plans, err := ratePlanService.ListPlans(c.Request.Context())
+		if err != nil {
+			writeRatePlanError(c, err, "Failed to list rate plans")
+			return
+		}
+		c.JSON(200, utils.Success(dto.RatePlansResponse{Status: "ok", Data: plans}))
+	}
+}
+
+// GetRatePlanHandler godoc
+// @Summary Lấy chi tiết gói hoàn phí
+// @Tags rate_plans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID gói hoàn phí"
+// @Success 200 {object} dto.APIResponse{data=models.RatePlan}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/rate-plans/{id} [get]
+func GetRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := ratePlanID(c)
+		if !ok {
+			return
+		}
+		plan, err := ratePlanService.GetPlan(c.Request.Context(), id)
+		if err != nil {
+			writeRatePlanError(c, err, "Failed to get rate plan")
+			return
+		}
+		c.JSON(200, utils.Success(plan))
+	}
+}
+
+// CreateRatePlanHandler godoc
+// @Summary Tạo gói hoàn phí
+// @Description Gói gồm các bậc theo khối lượng giao dịch, mỗi bậc có tỉ lệ hoàn phí theo market
+// @Tags rate_plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.RatePlanRequest true "Thông tin gói hoàn phí"
+// @Success 201 {object} dto.APIResponse{data=models.RatePlan}
+// @Failure 400,401,403,500 {object} dto.APIResponse
+// @Router /admin/rate-plans [post]
+func CreateRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.RatePlanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		plan, err := ratePlanService.CreatePlan(c.Request.Context(), models.RatePlan{
+			Name:       req.Name,
+			Exchange:   req.Exchange,
+			ReferrerID: req.ReferrerID,
+			Tiers:      req.Tiers,
+		})
+		if err != nil {
+			writeRatePlanError(c, err, "Failed to create rate plan")
+			return
+		}
+		c.JSON(201, utils.Success(plan))
+	}
+}
+
+// UpdateRatePlanHandler godoc
+// @Summary Cập nhật gói hoàn phí
+// @Tags rate_plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID gói hoàn phí"
+// @Param body body dto.RatePlanRequest true "Thông tin gói hoàn phí"
+// @Success 200 {object} dto.APIResponse{data=models.RatePlan}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/rate-plans/{id} [put]
+func UpdateRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := ratePlanID(c)
+		if !ok {
+			return
+		}
+		var req dto.RatePlanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		plan, err := ratePlanService.UpdatePlan(c.Request.Context(), models.RatePlan{
+			ID:         id,
+			Name:       req.Name,
+			Exchange:   req.Exchange,
+			ReferrerID: req.ReferrerID,
+			Tiers:      req.Tiers,
+		})
+		if err != nil {
+			writeRatePlanError(c, err, "Failed to update rate plan")
+			return
+		}
+		c.JSON(200, utils.Success(plan))
+	}
+}
+
+// DeleteRatePlanHandler godoc
+// @Summary Xóa gói hoàn phí
+// @Tags rate_plans
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID gói hoàn phí"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/rate-plans/{id} [delete]
+func DeleteRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := ratePlanID(c)
+		if !ok {
+			return
+		}
+		if err := ratePlanService.DeletePlan(c.Request.Context(), id); err != nil {
+			writeRatePlanError(c, err, "Failed to delete rate plan")
+			return
+		}
+		c.JSON(200, utils.Success(nil))
+	}
+}
+
+// AssignRatePlanHandler godoc
+// @Summary Gán gói hoàn phí cho tài khoản
+// @Description Gói được áp dụng cho các trade có thời gian từ effectiveFrom
+// @Tags rate_plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID gói hoàn phí"
+// @Param body body dto.AssignRatePlanRequest true "Tài khoản và thời điểm hiệu lực"
+// @Success 200 {object} dto.APIResponse
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /admin/rate-plans/{id}/assign [post]
+func AssignRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		planID, ok := ratePlanID(c)
+		if !ok {
+			return
+		}
+		var req dto.AssignRatePlanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		accountID, err := primitive.ObjectIDFromHex(req.RegisteredAccountID)
+		if err != nil {
+			logrus.WithField("error", err).Error("Invalid registered account ID")
+			c.JSON(400, utils.Error("ID tài khoản không hợp lệ"))
+			return
+		}
+		if err := ratePlanService.AssignPlan(c.Request.Context(), accountID, planID, req.EffectiveFrom); err != nil {
+			writeRatePlanError(c, err, "Failed to assign rate plan")
+			return
+		}
+		c.JSON(200, utils.Success(nil))
+	}
+}
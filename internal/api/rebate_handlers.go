@@ -0,0 +1,148 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// CalculateRebateHandler godoc
+// @Summary Tính hoàn phí cho tài khoản theo kỳ
+// @Description Dành cho operator/admin. Cộng phí giao dịch trong kỳ, quy đổi sang asset thanh toán và áp dụng tỉ lệ hoàn phí.
+// @Description Tính lại đúng kỳ đã có thì ghi đè, kỳ giao với statement đã có bị từ chối.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID tài khoản đã đăng ký"
+// @Param body body dto.CalculateRebateRequest true "Kỳ tính hoàn phí"
+// @Success 200 {object} dto.APIResponse{data=models.RebateStatement}
+// @Failure 400,401,403,404,409,500 {object} dto.APIResponse
+// @Router /admin/accounts/{id}/rebates/calculate [post]
+func CalculateRebateHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, ok := adminAccount(c, accountRepo)
+		if !ok {
+			return
+		}
+		var req dto.CalculateRebateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		if !req.PeriodEnd.After(req.PeriodStart) {
+			logrus.WithFields(logrus.Fields{
+				"period_start": req.PeriodStart,
+				"period_end":   req.PeriodEnd,
+			}).Error("Invalid rebate period")
+			c.JSON(400, utils.Error("Kỳ tính hoàn phí không hợp lệ"))
+			return
+		}
+		statement, err := rebateService.CalculateStatement(c.Request.Context(), account, req.PeriodStart, req.PeriodEnd)
+		if errors.Is(err, services.ErrStatementOverlap) {
+			c.JSON(409, utils.Error("Kỳ tính hoàn phí trùng với bảng hoàn phí đã có"))
+			return
+		}
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": account.ID.Hex(),
+				"error":                 err,
+			}).Error("Failed to calculate rebate statement")
+			c.JSON(500, utils.Error("Lỗi tính hoàn phí"))
+			return
+		}
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": account.ID.Hex(),
+			"operator_id":           currentUserID(c),
+			"period_start":          req.PeriodStart,
+			"period_end":            req.PeriodEnd,
+		}).Info("Rebate statement calculated by operator")
+		c.JSON(200, utils.Success(statement))
+	}
+}
+
+// GetRebatesHandler godoc
+// @Summary Lấy danh sách bảng hoàn phí của tài khoản
+// @Description Lấy các rebate statement đã tính theo registered_account_id
+// @Tags rebates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetRebatesRequest true "ID tài khoản đã đăng ký"
+// @Success 200 {object} dto.APIResponse{data=dto.RebateStatementsResponse}
+// @Failure 400,401,403,500 {object} dto.APIResponse
+// @Router /rebates [post]
+func GetRebatesHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetRebatesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		statements, err := rebateService.GetStatements(c.Request.Context(), account.ID)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get rebate statements")
+			c.JSON(500, utils.Error("Lỗi lấy danh sách hoàn phí"))
+			return
+		}
+		resp := dto.RebateStatementsResponse{
+			Status: "ok",
+			Data:   statements,
+		}
+		c.JSON(200, utils.Success(resp))
+	}
+}
+
+// GetFeesHandler godoc
+// @Summary Tổng hợp phí giao dịch của tài khoản
+// @Description Tổng phí theo từng asset trong khoảng thời gian, quy đổi sang asset thanh toán theo giá tại thời điểm giao dịch
+// @Tags rebates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetFeesRequest true "Tài khoản và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=models.FeeSummary}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /fees [post]
+func GetFeesHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetFeesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		summary, err := rebateService.SummarizeFees(c.Request.Context(), account, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to summarize fees")
+			c.JSON(500, utils.Error("Lỗi tổng hợp phí giao dịch"))
+			return
+		}
+		c.JSON(200, utils.Success(summary))
+	}
+}
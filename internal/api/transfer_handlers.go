@@ -0,0 +1,58 @@
+package api
+
+import (
+	"autobackcom/internal/api/dto"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetTransfersHandler godoc
+// @Summary Lịch sử nạp, rút và chuyển tiền nội bộ của tài khoản
+// @Description Lấy các lần nạp (DEPOSIT), rút (WITHDRAWAL) và chuyển tiền giữa các ví (TRANSFER) trong khoảng thời gian
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.GetTransfersRequest true "Tài khoản, loại và khoảng thời gian"
+// @Success 200 {object} dto.APIResponse{data=dto.TransfersResponse}
+// @Failure 400,401,403,404,500 {object} dto.APIResponse
+// @Router /transfers [post]
+func GetTransfersHandler(accountRepo *repositories.RegisteredAccountRepository, transferService *services.TransferService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req dto.GetTransfersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logrus.WithField("error", err).Error("Invalid request")
+			c.JSON(400, utils.Error("Yêu cầu không hợp lệ"))
+			return
+		}
+		switch req.Type {
+		case "", models.TransferTypeDeposit, models.TransferTypeWithdrawal, models.TransferTypeTransfer:
+		default:
+			c.JSON(400, utils.Error("Loại giao dịch không hợp lệ"))
+			return
+		}
+		account, ok := requireAccountOwner(c, accountRepo, req.RegisteredAccountID)
+		if !ok {
+			return
+		}
+		if !req.To.After(req.From) {
+			c.JSON(400, utils.Error("Khoảng thời gian không hợp lệ"))
+			return
+		}
+		transfers, err := transferService.GetTransfers(c.Request.Context(), account, req.Type, req.From, req.To)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"registered_account_id": req.RegisteredAccountID,
+				"error":                 err,
+			}).Error("Failed to get transfers")
+			c.JSON(500, utils.Error("Lỗi lấy lịch sử nạp/rút"))
+			return
+		}
+		c.JSON(200, utils.Success(dto.TransfersResponse{Status: "ok", Data: transfers}))
+	}
+}
@@ -0,0 +1,34 @@
+package cronjob
+
+import (
+	"autobackcom/internal/services"
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartBalanceSnapshotCron chụp số dư các tài khoản mỗi BALANCE_SNAPSHOT_CRON_MINUTES phút, mặc định 60
+func StartBalanceSnapshotCron(ctx context.Context, balanceService *services.BalanceService) {
+	minutes := 60
+	if m, err := strconv.Atoi(os.Getenv("BALANCE_SNAPSHOT_CRON_MINUTES")); err == nil && m > 0 {
+		minutes = m
+	}
+
+	// Chạy ngay khi khởi động
+	go func() {
+		if err := balanceService.SnapshotAllBalances(ctx); err != nil {
+			log.Println("[CRON] Immediate SnapshotAllBalances error:", err)
+		}
+	}()
+
+	c := cron.New()
+	c.AddFunc("@every "+strconv.Itoa(minutes)+"m", func() {
+		if err := balanceService.SnapshotAllBalances(ctx); err != nil {
+			log.Println("[CRON] SnapshotAllBalances error:", err)
+		}
+	})
+	c.Start()
+}
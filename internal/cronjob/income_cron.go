@@ -0,0 +1,34 @@
+package cronjob
+
+import (
+	"autobackcom/internal/services"
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartIncomeCron đồng bộ income futures mỗi INCOME_CRON_MINUTES phút, mặc định 60
+func StartIncomeCron(ctx context.Context, incomeService *services.IncomeService) {
+	minutes := 60
+	if m, err := strconv.Atoi(os.Getenv("INCOME_CRON_MINUTES")); err == nil && m > 0 {
+		minutes = m
+	}
+
+	// Chạy ngay khi khởi động
+	go func() {
+		if err := incomeService.SyncAllIncomes(ctx); err != nil {
+			log.Println("[CRON] Immediate SyncAllIncomes error:", err)
+		}
+	}()
+
+	c := cron.New()
+	c.AddFunc("@every "+strconv.Itoa(minutes)+"m", func() {
+		if err := incomeService.SyncAllIncomes(ctx); err != nil {
+			log.Println("[CRON] SyncAllIncomes error:", err)
+		}
+	})
+	c.Start()
+}
@@ -0,0 +1,35 @@
+package cronjob
+
+import (
+	"autobackcom/internal/services"
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartOrderHistoryCron đồng bộ lịch sử order mỗi ORDER_HISTORY_CRON_MINUTES phút, mặc định 30.
+// Futures chỉ giữ order bị hủy/hết hạn không có fill trong vài ngày nên không nên để quá thưa.
+func StartOrderHistoryCron(ctx context.Context, orderHistoryService *services.OrderHistoryService) {
+	minutes := 30
+	if m, err := strconv.Atoi(os.Getenv("ORDER_HISTORY_CRON_MINUTES")); err == nil && m > 0 {
+		minutes = m
+	}
+
+	// Chạy ngay khi khởi động
+	go func() {
+		if err := orderHistoryService.SyncAllOrderHistory(ctx); err != nil {
+			log.Println("[CRON] Immediate SyncAllOrderHistory error:", err)
+		}
+	}()
+
+	c := cron.New()
+	c.AddFunc("@every "+strconv.Itoa(minutes)+"m", func() {
+		if err := orderHistoryService.SyncAllOrderHistory(ctx); err != nil {
+			log.Println("[CRON] SyncAllOrderHistory error:", err)
+		}
+	})
+	c.Start()
+}
@@ -0,0 +1,35 @@
+package cronjob
+
+import (
+	"autobackcom/internal/services"
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartRebateCron tính hoàn phí của tháng trước cho tất cả account, mặc định 01:00 ngày 1 hàng tháng
+func StartRebateCron(ctx context.Context, rebateService *services.RebateService) {
+	cronSpec := os.Getenv("REBATE_CRON_SPEC")
+	if cronSpec == "" {
+		cronSpec = "0 1 1 * *"
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(cronSpec, func() {
+		now := time.Now().UTC()
+		end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		start := end.AddDate(0, -1, 0)
+		err := rebateService.CalculateAllStatements(ctx, start, end)
+		if err != nil {
+			log.Println("[CRON] CalculateAllStatements error:", err)
+		}
+	})
+	if err != nil {
+		log.Println("[CRON] Invalid REBATE_CRON_SPEC:", err)
+		return
+	}
+	c.Start()
+}
@@ -0,0 +1,34 @@
+package cronjob
+
+import (
+	"autobackcom/internal/services"
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StartTransferCron đồng bộ lịch sử nạp/rút/chuyển tiền mỗi TRANSFER_CRON_MINUTES phút, mặc định 60
+func StartTransferCron(ctx context.Context, transferService *services.TransferService) {
+	minutes := 60
+	if m, err := strconv.Atoi(os.Getenv("TRANSFER_CRON_MINUTES")); err == nil && m > 0 {
+		minutes = m
+	}
+
+	// Chạy ngay khi khởi động
+	go func() {
+		if err := transferService.SyncAllTransfers(ctx); err != nil {
+			log.Println("[CRON] Immediate SyncAllTransfers error:", err)
+		}
+	}()
+
+	c := cron.New()
+	c.AddFunc("@every "+strconv.Itoa(minutes)+"m", func() {
+		if err := transferService.SyncAllTransfers(ctx); err != nil {
+			log.Println("[CRON] SyncAllTransfers error:", err)
+		}
+	})
+	c.Start()
+}
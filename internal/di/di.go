@@ -3,8 +3,13 @@ package di
 import (
 	"autobackcom/internal/api"
 	"autobackcom/internal/exchanges"
+	"autobackcom/internal/exchanges/binance"
+	"autobackcom/internal/exchanges/bybit"
+	"autobackcom/internal/exchanges/okx"
 	"autobackcom/internal/repositories"
+	"autobackcom/internal/secrets"
 	"autobackcom/internal/services"
+	"autobackcom/internal/utils"
 	"context"
 	"fmt"
 	"os"
@@ -17,9 +22,38 @@ import (
 )
 
 type AppHandlers struct {
-	RegisterHandler       gin.HandlerFunc `name:"register"`
-	GetOrdersHandler      gin.HandlerFunc `name:"getOrders"`
-	FetchAllTradesHandler gin.HandlerFunc `name:"fetchAllTrades"`
+	SignupHandler                   gin.HandlerFunc `name:"signup"`
+	RegisterHandler                 gin.HandlerFunc `name:"register"`
+	LoginHandler                    gin.HandlerFunc `name:"login"`
+	APIKeyLoginHandler              gin.HandlerFunc `name:"apiKeyLogin"`
+	ListExchangesHandler            gin.HandlerFunc `name:"listExchanges"`
+	ListAccountsHandler             gin.HandlerFunc `name:"listAccounts"`
+	DeleteAccountHandler            gin.HandlerFunc `name:"deleteAccount"`
+	UpdateAccountKeysHandler        gin.HandlerFunc `name:"updateAccountKeys"`
+	UpdateAccountStatusHandler      gin.HandlerFunc `name:"updateAccountStatus"`
+	GetOrdersHandler                gin.HandlerFunc `name:"getOrders"`
+	GetAggregatedOrdersHandler      gin.HandlerFunc `name:"getAggregatedOrders"`
+	GetOrderHistoryHandler          gin.HandlerFunc `name:"getOrderHistory"`
+	FetchAllTradesHandler           gin.HandlerFunc `name:"fetchAllTrades"`
+	AdminListAccountsHandler        gin.HandlerFunc `name:"adminListAccounts"`
+	AdminSyncStatusHandler          gin.HandlerFunc `name:"adminSyncStatus"`
+	AdminSyncAccountHandler         gin.HandlerFunc `name:"adminSyncAccount"`
+	AdminUpdateAccountStatusHandler gin.HandlerFunc `name:"adminUpdateAccountStatus"`
+	AdminSetUserRoleHandler         gin.HandlerFunc `name:"adminSetUserRole"`
+	CalculateRebateHandler          gin.HandlerFunc `name:"calculateRebate"`
+	GetRebatesHandler               gin.HandlerFunc `name:"getRebates"`
+	GetFeesHandler                  gin.HandlerFunc `name:"getFees"`
+	GetIncomesHandler               gin.HandlerFunc `name:"getIncomes"`
+	GetIncomeSummaryHandler         gin.HandlerFunc `name:"getIncomeSummary"`
+	GetTransfersHandler             gin.HandlerFunc `name:"getTransfers"`
+	GetBalanceSnapshotsHandler      gin.HandlerFunc `name:"getBalanceSnapshots"`
+	GetDailyEquityHandler           gin.HandlerFunc `name:"getDailyEquity"`
+	ListRatePlansHandler            gin.HandlerFunc `name:"listRatePlans"`
+	GetRatePlanHandler              gin.HandlerFunc `name:"getRatePlan"`
+	CreateRatePlanHandler           gin.HandlerFunc `name:"createRatePlan"`
+	UpdateRatePlanHandler           gin.HandlerFunc `name:"updateRatePlan"`
+	DeleteRatePlanHandler           gin.HandlerFunc `name:"deleteRatePlan"`
+	AssignRatePlanHandler           gin.HandlerFunc `name:"assignRatePlan"`
 }
 
 // Provider cho MongoDB client
@@ -38,23 +72,142 @@ func NewRegisteredAccountRepository(client *mongo.Client) *repositories.Register
 	return repositories.NewRegisteredAccountRepository(client, "exchange_db", "registered_accounts")
 }
 
+// Provider cho UserRepository
+func NewUserRepository(client *mongo.Client) *repositories.UserRepository {
+	return repositories.NewUserRepository(client, "exchange_db", "users")
+}
+
 // Provider cho OrderRepository
 func NewOrderRepository(client *mongo.Client) *repositories.OrderRepository {
 	return repositories.NewOrderRepository(client, "exchange_db", "orders")
 }
 
-// Provider cho ExchangeService (nếu cần gom fetcher vào map)
-type ExchangeServiceDeps struct {
-	dig.In
-	RegisteredAccountRepo *repositories.RegisteredAccountRepository
-	OrderRepo             *repositories.OrderRepository
-	BinanceSpotFetcher    exchanges.ExchangeFetcher `name:"binanceSpot"`
-	BinanceFuturesFetcher exchanges.ExchangeFetcher `name:"binanceFutures"`
+// Provider cho SyncStateRepository
+func NewSyncStateRepository(client *mongo.Client) *repositories.SyncStateRepository {
+	return repositories.NewSyncStateRepository(client, "exchange_db", "sync_state")
+}
+
+// Provider cho IncomeRepository
+func NewIncomeRepository(client *mongo.Client) *repositories.IncomeRepository {
+	return repositories.NewIncomeRepository(client, "exchange_db", "incomes")
+}
+
+// Provider cho TransferRepository
+func NewTransferRepository(client *mongo.Client) *repositories.TransferRepository {
+	return repositories.NewTransferRepository(client, "exchange_db", "transfers")
+}
+
+// Provider cho BalanceSnapshotRepository
+func NewBalanceSnapshotRepository(client *mongo.Client) *repositories.BalanceSnapshotRepository {
+	return repositories.NewBalanceSnapshotRepository(client, "exchange_db", "balance_snapshots")
+}
+
+// Provider cho OrderHistoryRepository
+func NewOrderHistoryRepository(client *mongo.Client) *repositories.OrderHistoryRepository {
+	return repositories.NewOrderHistoryRepository(client, "exchange_db", "order_history")
+}
+
+// Provider cho RebateStatementRepository
+func NewRebateStatementRepository(client *mongo.Client) *repositories.RebateStatementRepository {
+	return repositories.NewRebateStatementRepository(client, "exchange_db", "rebate_statements")
+}
+
+// Provider cho RatePlanRepository
+func NewRatePlanRepository(client *mongo.Client) *repositories.RatePlanRepository {
+	return repositories.NewRatePlanRepository(client, "exchange_db", "rate_plans")
+}
+
+// Provider cho PriceRepository
+func NewPriceRepository(client *mongo.Client) *repositories.PriceRepository {
+	return repositories.NewPriceRepository(client, "exchange_db", "prices")
+}
+
+// Provider cho SecretStore, chọn backend theo SECRET_STORE
+func NewSecretStore() (secrets.SecretStore, error) {
+	return secrets.NewSecretStoreFromEnv(utils.DefaultKeyring())
+}
+
+// Provider cho PriceService, quy đổi sang asset thanh toán theo giá kline Binance
+func NewPriceService(priceRepo *repositories.PriceRepository) *services.PriceService {
+	return services.NewPriceService(binance.NewBinancePriceFeed(), priceRepo, services.SettlementAsset())
+}
+
+// Provider cho BalanceService, equity luôn quy đổi sang USDT nên dùng PriceService riêng
+// thay vì AssetConverter theo asset thanh toán hoàn phí
+func NewBalanceService(accountRepo *repositories.RegisteredAccountRepository, snapshotRepo *repositories.BalanceSnapshotRepository, clientManager *services.ClientManagerService, priceRepo *repositories.PriceRepository) *services.BalanceService {
+	converter := services.NewPriceService(binance.NewBinancePriceFeed(), priceRepo, services.EquityAsset)
+	return services.NewBalanceService(accountRepo, snapshotRepo, clientManager, converter)
+}
+
+// Provider cho AssetConverter dùng khi tính hoàn phí
+func NewAssetConverter(priceService *services.PriceService) services.AssetConverter {
+	return priceService
+}
+
+// Provider cho exchanges.Registry, mỗi exchange mới chỉ cần đăng ký adapter tại đây
+func NewExchangeRegistry() (*exchanges.Registry, error) {
+	registry := exchanges.NewRegistry()
+	for _, adapter := range []exchanges.Adapter{binance.Adapter(), okx.Adapter(), bybit.Adapter()} {
+		if err := registry.Register(adapter); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Provider cho SignupHandler
+func NewSignupHandler(userService *services.UserService) gin.HandlerFunc {
+	return api.SignupHandler(userService)
 }
 
 // Provider cho RegisterHandler
-func NewRegisterHandler(accountRepo *repositories.RegisteredAccountRepository, tradeHistoryService *services.TradeHistoryService) gin.HandlerFunc {
-	return api.RegisterHandler(accountRepo, tradeHistoryService)
+func NewRegisterHandler(accountRepo *repositories.RegisteredAccountRepository, userService *services.UserService, clientManager *services.ClientManagerService, secretStore secrets.SecretStore, tradeHistoryService *services.TradeHistoryService, registry *exchanges.Registry) gin.HandlerFunc {
+	return api.RegisterHandler(accountRepo, userService, clientManager, secretStore, tradeHistoryService, registry)
+}
+
+// Provider cho LoginHandler
+func NewLoginHandler(userService *services.UserService) gin.HandlerFunc {
+	return api.LoginHandler(userService)
+}
+
+// Provider cho APIKeyLoginHandler
+func NewAPIKeyLoginHandler(accountRepo *repositories.RegisteredAccountRepository, userService *services.UserService, secretStore secrets.SecretStore) gin.HandlerFunc {
+	return api.APIKeyLoginHandler(accountRepo, userService, secretStore)
+}
+
+// Provider cho ListExchangesHandler
+func NewListExchangesHandler(registry *exchanges.Registry) gin.HandlerFunc {
+	return api.ListExchangesHandler(registry)
+}
+
+// Provider cho ListAccountsHandler
+func NewListAccountsHandler(accountRepo *repositories.RegisteredAccountRepository) gin.HandlerFunc {
+	return api.ListAccountsHandler(accountRepo)
+}
+
+// Provider cho DeleteAccountHandler
+func NewDeleteAccountHandler(accountRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository, orderHistoryRepo *repositories.OrderHistoryRepository, incomeRepo *repositories.IncomeRepository, transferRepo *repositories.TransferRepository, snapshotRepo *repositories.BalanceSnapshotRepository, syncStateRepo *repositories.SyncStateRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService, secretStore secrets.SecretStore) gin.HandlerFunc {
+	return api.DeleteAccountHandler(accountRepo, orderRepo, orderHistoryRepo, incomeRepo, transferRepo, snapshotRepo, syncStateRepo, clientManager, userStreams, secretStore)
+}
+
+// Provider cho UpdateAccountKeysHandler
+func NewUpdateAccountKeysHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService, secretStore secrets.SecretStore, registry *exchanges.Registry) gin.HandlerFunc {
+	return api.UpdateAccountKeysHandler(accountRepo, clientManager, userStreams, secretStore, registry)
+}
+
+// Provider cho UpdateAccountStatusHandler
+func NewUpdateAccountStatusHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService) gin.HandlerFunc {
+	return api.UpdateAccountStatusHandler(accountRepo, clientManager, userStreams)
+}
+
+// Provider cho GetAggregatedOrdersHandler
+func NewGetAggregatedOrdersHandler(accountRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository) gin.HandlerFunc {
+	return api.GetAggregatedOrdersHandler(accountRepo, orderRepo)
+}
+
+// Provider cho GetOrderHistoryHandler
+func NewGetOrderHistoryHandler(accountRepo *repositories.RegisteredAccountRepository, orderHistoryService *services.OrderHistoryService) gin.HandlerFunc {
+	return api.GetOrderHistoryHandler(accountRepo, orderHistoryService)
 }
 
 // Provider cho GetOrdersHandler
@@ -62,10 +215,100 @@ func NewGetOrdersHandler(accountRepo *repositories.RegisteredAccountRepository,
 	return api.GetOrdersHandler(accountRepo, orderRepo)
 }
 
+// Provider cho GetIncomesHandler
+func NewGetIncomesHandler(accountRepo *repositories.RegisteredAccountRepository, incomeService *services.IncomeService) gin.HandlerFunc {
+	return api.GetIncomesHandler(accountRepo, incomeService)
+}
+
+// Provider cho GetTransfersHandler
+func NewGetTransfersHandler(accountRepo *repositories.RegisteredAccountRepository, transferService *services.TransferService) gin.HandlerFunc {
+	return api.GetTransfersHandler(accountRepo, transferService)
+}
+
+// Provider cho GetBalanceSnapshotsHandler
+func NewGetBalanceSnapshotsHandler(accountRepo *repositories.RegisteredAccountRepository, balanceService *services.BalanceService) gin.HandlerFunc {
+	return api.GetBalanceSnapshotsHandler(accountRepo, balanceService)
+}
+
+// Provider cho GetDailyEquityHandler
+func NewGetDailyEquityHandler(accountRepo *repositories.RegisteredAccountRepository, balanceService *services.BalanceService) gin.HandlerFunc {
+	return api.GetDailyEquityHandler(accountRepo, balanceService)
+}
+
+// Provider cho GetIncomeSummaryHandler
+func NewGetIncomeSummaryHandler(accountRepo *repositories.RegisteredAccountRepository, incomeService *services.IncomeService) gin.HandlerFunc {
+	return api.GetIncomeSummaryHandler(accountRepo, incomeService)
+}
+
 func NewFetchAllTradeOfUsersHandler(tradeHistoryService *services.TradeHistoryService) gin.HandlerFunc {
 	return api.FetchAllTradesForUser(tradeHistoryService)
 }
 
+// Provider cho AdminListAccountsHandler
+func NewAdminListAccountsHandler(accountRepo *repositories.RegisteredAccountRepository) gin.HandlerFunc {
+	return api.AdminListAccountsHandler(accountRepo)
+}
+
+// Provider cho AdminSyncStatusHandler
+func NewAdminSyncStatusHandler(accountRepo *repositories.RegisteredAccountRepository, syncStateRepo *repositories.SyncStateRepository) gin.HandlerFunc {
+	return api.AdminSyncStatusHandler(accountRepo, syncStateRepo)
+}
+
+// Provider cho AdminSyncAccountHandler
+func NewAdminSyncAccountHandler(accountRepo *repositories.RegisteredAccountRepository, tradeHistoryService *services.TradeHistoryService, incomeService *services.IncomeService, orderHistoryService *services.OrderHistoryService, transferService *services.TransferService) gin.HandlerFunc {
+	return api.AdminSyncAccountHandler(accountRepo, tradeHistoryService, incomeService, orderHistoryService, transferService)
+}
+
+// Provider cho AdminUpdateAccountStatusHandler
+func NewAdminUpdateAccountStatusHandler(accountRepo *repositories.RegisteredAccountRepository, clientManager *services.ClientManagerService, userStreams *services.UserStreamService) gin.HandlerFunc {
+	return api.AdminUpdateAccountStatusHandler(accountRepo, clientManager, userStreams)
+}
+
+// Provider cho AdminSetUserRoleHandler
+func NewAdminSetUserRoleHandler(userService *services.UserService) gin.HandlerFunc {
+	return api.AdminSetUserRoleHandler(userService)
+}
+
+// Provider cho CalculateRebateHandler
+func NewCalculateRebateHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return api.CalculateRebateHandler(accountRepo, rebateService)
+}
+
+// Provider cho GetRebatesHandler
+func NewGetRebatesHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return api.GetRebatesHandler(accountRepo, rebateService)
+}
+
+// Provider cho GetFeesHandler
+func NewGetFeesHandler(accountRepo *repositories.RegisteredAccountRepository, rebateService *services.RebateService) gin.HandlerFunc {
+	return api.GetFeesHandler(accountRepo, rebateService)
+}
+
+// Provider cho các handler quản lý gói hoàn phí
+func NewListRatePlansHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.ListRatePlansHandler(ratePlanService)
+}
+
+func NewGetRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.GetRatePlanHandler(ratePlanService)
+}
+
+func NewCreateRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.CreateRatePlanHandler(ratePlanService)
+}
+
+func NewUpdateRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.UpdateRatePlanHandler(ratePlanService)
+}
+
+func NewDeleteRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.DeleteRatePlanHandler(ratePlanService)
+}
+
+func NewAssignRatePlanHandler(ratePlanService *services.RatePlanService) gin.HandlerFunc {
+	return api.AssignRatePlanHandler(ratePlanService)
+}
+
 func BuildContainer() (*dig.Container, error) {
 	c := dig.New()
 	c.Provide(func() string {
@@ -77,25 +320,133 @@ func BuildContainer() (*dig.Container, error) {
 	})
 	c.Provide(NewMongoClient)
 	c.Provide(NewRegisteredAccountRepository)
+	c.Provide(NewUserRepository)
 	c.Provide(NewOrderRepository)
+	c.Provide(NewSyncStateRepository)
+	c.Provide(NewIncomeRepository)
+	c.Provide(NewOrderHistoryRepository)
+	c.Provide(NewTransferRepository)
+	c.Provide(NewBalanceSnapshotRepository)
+	c.Provide(NewRebateStatementRepository)
+	c.Provide(NewRatePlanRepository)
+	c.Provide(NewPriceRepository)
+	c.Provide(NewPriceService)
+	c.Provide(NewAssetConverter)
+	c.Provide(NewSecretStore)
+	c.Provide(NewExchangeRegistry)
 	c.Provide(services.NewClientManagerService)
-	c.Provide(func(accountRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository, clientManager *services.ClientManagerService) *services.TradeHistoryService {
-		return services.NewTradeHistoryService(accountRepo, orderRepo, clientManager)
+	c.Provide(func(accountRepo *repositories.RegisteredAccountRepository, orderRepo *repositories.OrderRepository, syncStateRepo *repositories.SyncStateRepository, clientManager *services.ClientManagerService) *services.TradeHistoryService {
+		return services.NewTradeHistoryService(accountRepo, orderRepo, syncStateRepo, clientManager)
 	})
+	c.Provide(services.NewIncomeService)
+	c.Provide(services.NewOrderHistoryService)
+	c.Provide(services.NewTransferService)
+	c.Provide(NewBalanceService)
+	c.Provide(services.NewUserStreamService)
+	c.Provide(services.NewUserService)
+	c.Provide(services.NewRatePlanService)
+	c.Provide(services.NewRebateService)
+	c.Provide(NewSignupHandler, dig.Name("signup"))
 	c.Provide(NewRegisterHandler, dig.Name("register"))
+	c.Provide(NewLoginHandler, dig.Name("login"))
+	c.Provide(NewAPIKeyLoginHandler, dig.Name("apiKeyLogin"))
+	c.Provide(NewListExchangesHandler, dig.Name("listExchanges"))
+	c.Provide(NewListAccountsHandler, dig.Name("listAccounts"))
+	c.Provide(NewDeleteAccountHandler, dig.Name("deleteAccount"))
+	c.Provide(NewUpdateAccountKeysHandler, dig.Name("updateAccountKeys"))
+	c.Provide(NewUpdateAccountStatusHandler, dig.Name("updateAccountStatus"))
 	c.Provide(NewGetOrdersHandler, dig.Name("getOrders"))
+	c.Provide(NewGetAggregatedOrdersHandler, dig.Name("getAggregatedOrders"))
+	c.Provide(NewGetOrderHistoryHandler, dig.Name("getOrderHistory"))
 	c.Provide(NewFetchAllTradeOfUsersHandler, dig.Name("fetchAllTrades"))
+	c.Provide(NewAdminListAccountsHandler, dig.Name("adminListAccounts"))
+	c.Provide(NewAdminSyncStatusHandler, dig.Name("adminSyncStatus"))
+	c.Provide(NewAdminSyncAccountHandler, dig.Name("adminSyncAccount"))
+	c.Provide(NewAdminUpdateAccountStatusHandler, dig.Name("adminUpdateAccountStatus"))
+	c.Provide(NewAdminSetUserRoleHandler, dig.Name("adminSetUserRole"))
+	c.Provide(NewCalculateRebateHandler, dig.Name("calculateRebate"))
+	c.Provide(NewGetRebatesHandler, dig.Name("getRebates"))
+	c.Provide(NewGetFeesHandler, dig.Name("getFees"))
+	c.Provide(NewGetIncomesHandler, dig.Name("getIncomes"))
+	c.Provide(NewGetIncomeSummaryHandler, dig.Name("getIncomeSummary"))
+	c.Provide(NewGetTransfersHandler, dig.Name("getTransfers"))
+	c.Provide(NewGetBalanceSnapshotsHandler, dig.Name("getBalanceSnapshots"))
+	c.Provide(NewGetDailyEquityHandler, dig.Name("getDailyEquity"))
+	c.Provide(NewListRatePlansHandler, dig.Name("listRatePlans"))
+	c.Provide(NewGetRatePlanHandler, dig.Name("getRatePlan"))
+	c.Provide(NewCreateRatePlanHandler, dig.Name("createRatePlan"))
+	c.Provide(NewUpdateRatePlanHandler, dig.Name("updateRatePlan"))
+	c.Provide(NewDeleteRatePlanHandler, dig.Name("deleteRatePlan"))
+	c.Provide(NewAssignRatePlanHandler, dig.Name("assignRatePlan"))
 	type appHandlerIn struct {
 		dig.In
-		RegisterHandler       gin.HandlerFunc `name:"register"`
-		GetOrdersHandler      gin.HandlerFunc `name:"getOrders"`
-		FetchAllTradesHandler gin.HandlerFunc `name:"fetchAllTrades"`
+		SignupHandler                   gin.HandlerFunc `name:"signup"`
+		RegisterHandler                 gin.HandlerFunc `name:"register"`
+		LoginHandler                    gin.HandlerFunc `name:"login"`
+		APIKeyLoginHandler              gin.HandlerFunc `name:"apiKeyLogin"`
+		ListExchangesHandler            gin.HandlerFunc `name:"listExchanges"`
+		ListAccountsHandler             gin.HandlerFunc `name:"listAccounts"`
+		DeleteAccountHandler            gin.HandlerFunc `name:"deleteAccount"`
+		UpdateAccountKeysHandler        gin.HandlerFunc `name:"updateAccountKeys"`
+		UpdateAccountStatusHandler      gin.HandlerFunc `name:"updateAccountStatus"`
+		GetOrdersHandler                gin.HandlerFunc `name:"getOrders"`
+		GetAggregatedOrdersHandler      gin.HandlerFunc `name:"getAggregatedOrders"`
+		GetOrderHistoryHandler          gin.HandlerFunc `name:"getOrderHistory"`
+		FetchAllTradesHandler           gin.HandlerFunc `name:"fetchAllTrades"`
+		AdminListAccountsHandler        gin.HandlerFunc `name:"adminListAccounts"`
+		AdminSyncStatusHandler          gin.HandlerFunc `name:"adminSyncStatus"`
+		AdminSyncAccountHandler         gin.HandlerFunc `name:"adminSyncAccount"`
+		AdminUpdateAccountStatusHandler gin.HandlerFunc `name:"adminUpdateAccountStatus"`
+		AdminSetUserRoleHandler         gin.HandlerFunc `name:"adminSetUserRole"`
+		CalculateRebateHandler          gin.HandlerFunc `name:"calculateRebate"`
+		GetRebatesHandler               gin.HandlerFunc `name:"getRebates"`
+		GetFeesHandler                  gin.HandlerFunc `name:"getFees"`
+		GetIncomesHandler               gin.HandlerFunc `name:"getIncomes"`
+		GetIncomeSummaryHandler         gin.HandlerFunc `name:"getIncomeSummary"`
+		GetTransfersHandler             gin.HandlerFunc `name:"getTransfers"`
+		GetBalanceSnapshotsHandler      gin.HandlerFunc `name:"getBalanceSnapshots"`
+		GetDailyEquityHandler           gin.HandlerFunc `name:"getDailyEquity"`
+		ListRatePlansHandler            gin.HandlerFunc `name:"listRatePlans"`
+		GetRatePlanHandler              gin.HandlerFunc `name:"getRatePlan"`
+		CreateRatePlanHandler           gin.HandlerFunc `name:"createRatePlan"`
+		UpdateRatePlanHandler           gin.HandlerFunc `name:"updateRatePlan"`
+		DeleteRatePlanHandler           gin.HandlerFunc `name:"deleteRatePlan"`
+		AssignRatePlanHandler           gin.HandlerFunc `name:"assignRatePlan"`
 	}
 	c.Provide(func(in appHandlerIn) *AppHandlers {
 		return &AppHandlers{
-			RegisterHandler:       in.RegisterHandler,
-			GetOrdersHandler:      in.GetOrdersHandler,
-			FetchAllTradesHandler: in.FetchAllTradesHandler,
+			SignupHandler:                   in.SignupHandler,
+			RegisterHandler:                 in.RegisterHandler,
+			LoginHandler:                    in.LoginHandler,
+			APIKeyLoginHandler:              in.APIKeyLoginHandler,
+			ListExchangesHandler:            in.ListExchangesHandler,
+			ListAccountsHandler:             in.ListAccountsHandler,
+			DeleteAccountHandler:            in.DeleteAccountHandler,
+			UpdateAccountKeysHandler:        in.UpdateAccountKeysHandler,
+			UpdateAccountStatusHandler:      in.UpdateAccountStatusHandler,
+			GetOrdersHandler:                in.GetOrdersHandler,
+			GetAggregatedOrdersHandler:      in.GetAggregatedOrdersHandler,
+			GetOrderHistoryHandler:          in.GetOrderHistoryHandler,
+			FetchAllTradesHandler:           in.FetchAllTradesHandler,
+			AdminListAccountsHandler:        in.AdminListAccountsHandler,
+			AdminSyncStatusHandler:          in.AdminSyncStatusHandler,
+			AdminSyncAccountHandler:         in.AdminSyncAccountHandler,
+			AdminUpdateAccountStatusHandler: in.AdminUpdateAccountStatusHandler,
+			AdminSetUserRoleHandler:         in.AdminSetUserRoleHandler,
+			CalculateRebateHandler:          in.CalculateRebateHandler,
+			GetRebatesHandler:               in.GetRebatesHandler,
+			GetFeesHandler:                  in.GetFeesHandler,
+			GetIncomesHandler:               in.GetIncomesHandler,
+			GetIncomeSummaryHandler:         in.GetIncomeSummaryHandler,
+			GetTransfersHandler:             in.GetTransfersHandler,
+			GetBalanceSnapshotsHandler:      in.GetBalanceSnapshotsHandler,
+			GetDailyEquityHandler:           in.GetDailyEquityHandler,
+			ListRatePlansHandler:            in.ListRatePlansHandler,
+			GetRatePlanHandler:              in.GetRatePlanHandler,
+			CreateRatePlanHandler:           in.CreateRatePlanHandler,
+			UpdateRatePlanHandler:           in.UpdateRatePlanHandler,
+			DeleteRatePlanHandler:           in.DeleteRatePlanHandler,
+			AssignRatePlanHandler:           in.AssignRatePlanHandler,
 		}
 	})
 	// Đăng ký cleanup cho ClientManagerService
@@ -0,0 +1,64 @@
+package binance
+
+import "autobackcom/internal/exchanges"
+
+// newTransferFetcher dùng chung cho mọi market vì lịch sử ví gắn với API key, không theo market
+func newTransferFetcher(creds exchanges.Credentials) exchanges.TransferFetcher {
+	return NewBinanceTransferFetcher(creds.APIKey, creds.Secret)
+}
+
+// Adapter mô tả các market Binance cho exchanges.Registry
+func Adapter() exchanges.Adapter {
+	return exchanges.Adapter{
+		Exchange: "binance",
+		Markets: []exchanges.Market{
+			{
+				Name:            "spot",
+				SupportsTestnet: true,
+				NewOrderHistoryFetcher: func(creds exchanges.Credentials, isTestnet bool) exchanges.OrderHistoryFetcher {
+					return NewBinanceSpotExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+				NewUserStreamer: func(creds exchanges.Credentials, isTestnet bool) exchanges.UserStreamer {
+					return NewBinanceSpotExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+				NewTransferFetcher: newTransferFetcher,
+				NewBalanceFetcher: func(creds exchanges.Credentials, isTestnet bool) exchanges.BalanceFetcher {
+					return NewBinanceSpotExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+			},
+			{
+				Name:            "futures",
+				SupportsTestnet: true,
+				NewIncomeFetcher: func(creds exchanges.Credentials, isTestnet bool) exchanges.IncomeFetcher {
+					return NewBinanceFuturesIncomeFetcher(creds.APIKey, creds.Secret, isTestnet)
+				},
+				NewOrderHistoryFetcher: func(creds exchanges.Credentials, isTestnet bool) exchanges.OrderHistoryFetcher {
+					return NewBinanceFetureExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+				NewUserStreamer: func(creds exchanges.Credentials, isTestnet bool) exchanges.UserStreamer {
+					return NewBinanceFetureExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+				NewTransferFetcher: newTransferFetcher,
+				NewBalanceFetcher: func(creds exchanges.Credentials, isTestnet bool) exchanges.BalanceFetcher {
+					return NewBinanceFetureExchange(creds.APIKey, creds.Secret, isTestnet)
+				},
+			},
+			{Name: "coinm", SupportsTestnet: true, NewTransferFetcher: newTransferFetcher},
+			// Margin dùng endpoint sapi, không có trên testnet
+			{Name: "margin", NewTransferFetcher: newTransferFetcher},
+			{Name: "isolated_margin", NewTransferFetcher: newTransferFetcher},
+		},
+		New: func(market string, creds exchanges.Credentials, isTestnet bool) (exchanges.ExchangeFetcher, error) {
+			switch market {
+			case "spot":
+				return NewBinanceSpotExchange(creds.APIKey, creds.Secret, isTestnet), nil
+			case "futures":
+				return NewBinanceFetureExchange(creds.APIKey, creds.Secret, isTestnet), nil
+			case "coinm":
+				return NewBinanceCoinMExchange(creds.APIKey, creds.Secret, isTestnet), nil
+			default:
+				return NewBinanceMarginExchange(creds.APIKey, creds.Secret, market == "isolated_margin"), nil
+			}
+		},
+	}
+}
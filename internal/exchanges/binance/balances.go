@@ -0,0 +1,60 @@
+package binance
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"log"
+)
+
+// FetchBalances đọc số dư spot hiện tại, bỏ qua asset có số dư bằng 0
+func (b *BinanceSpotExchange) FetchBalances(ctx context.Context) ([]models.AssetBalance, error) {
+	account, err := b.client.NewGetAccountService().OmitZeroBalances(true).Do(ctx)
+	if err != nil {
+		log.Printf("Failed to fetch Binance spot balances: %v", err)
+		return nil, err
+	}
+	var balances []models.AssetBalance
+	for _, balance := range account.Balances {
+		free := models.DecimalFromString(balance.Free)
+		locked := models.DecimalFromString(balance.Locked)
+		total := free.Add(locked.Decimal)
+		if total.IsZero() {
+			continue
+		}
+		balances = append(balances, models.AssetBalance{
+			Asset:  balance.Asset,
+			Free:   free,
+			Locked: locked,
+			Equity: models.NewDecimal(total),
+		})
+	}
+	return balances, nil
+}
+
+// FetchBalances đọc số dư ví và margin futures theo từng asset, bỏ qua asset không có số dư
+// và không có PnL chưa chốt
+func (b *BinanceFeatureExchange) FetchBalances(ctx context.Context) ([]models.AssetBalance, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		log.Printf("Failed to fetch Binance futures balances: %v", err)
+		return nil, err
+	}
+	var balances []models.AssetBalance
+	for _, asset := range account.Assets {
+		wallet := models.DecimalFromString(asset.WalletBalance)
+		unrealized := models.DecimalFromString(asset.UnrealizedProfit)
+		if wallet.IsZero() && unrealized.IsZero() {
+			continue
+		}
+		margin := models.DecimalFromString(asset.MarginBalance)
+		balances = append(balances, models.AssetBalance{
+			Asset:            asset.Asset,
+			Free:             models.DecimalFromString(asset.AvailableBalance),
+			WalletBalance:    wallet,
+			UnrealizedProfit: unrealized,
+			MarginBalance:    margin,
+			Equity:           margin,
+		})
+	}
+	return balances, nil
+}
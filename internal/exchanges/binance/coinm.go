@@ -0,0 +1,217 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số trade tối đa Binance trả về cho một lần gọi /dapi/v1/userTrades
+	coinmTradeLimit = 1000
+)
+
+// BinanceCoinMExchange lấy trade của tài khoản COIN-M futures (hợp đồng ký quỹ bằng coin)
+type BinanceCoinMExchange struct {
+	client *delivery.Client
+	// Client spot cùng key, chỉ dùng gọi apiRestrictions (nil với testnet)
+	spotClient *binance.Client
+}
+
+func NewBinanceCoinMExchange(apiKey, secret string, isTestnet bool) *BinanceCoinMExchange {
+	delivery.UseTestnet = isTestnet
+	exchange := &BinanceCoinMExchange{client: delivery.NewClient(apiKey, secret)}
+	if !isTestnet {
+		exchange.spotClient = binance.NewClient(apiKey, secret)
+		exchange.spotClient.BaseURL = binance.BaseAPIMainURL
+	}
+	return exchange
+}
+
+// KeyPermissions đọc quyền của key qua apiRestrictions, với testnet chỉ kiểm tra key
+// bằng COIN-M account info
+func (b *BinanceCoinMExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	if b.spotClient == nil {
+		account, err := b.client.NewGetAccountService().Do(ctx)
+		if err != nil {
+			return models.APIKeyPermissions{}, wrapKeyError(err)
+		}
+		return models.APIKeyPermissions{
+			Reading:        true,
+			FuturesTrading: account.CanTrade,
+			Withdrawals:    account.CanWithdraw,
+			CheckedAt:      time.Now(),
+		}, nil
+	}
+	restrictions, err := b.spotClient.NewGetAPIKeyPermission().Do(ctx)
+	if err != nil {
+		return models.APIKeyPermissions{}, wrapKeyError(err)
+	}
+	return permissionsFromRestrictions(restrictions), nil
+}
+
+// FetchTrades lấy trade COIN-M theo từng symbol như spot: symbol có cursor đi tiếp từ trade ID
+// sau cursor, symbol chưa từng đồng bộ lấy lại từ trade đầu tiên Binance còn giữ
+func (b *BinanceCoinMExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	symbols, err := b.discoverSymbols(ctx, cursors)
+	if err != nil {
+		log.Printf("Failed to discover Binance COIN-M symbols for user %s: %v", registedAccountID, err)
+		return nil, err
+	}
+	var orders []models.Order
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range symbols {
+		var fromID int64
+		if cursor, ok := cursors[symbol]; ok {
+			fromID = cursor.LastTradeID + 1
+		}
+		symbolOrders, err := b.fetchSymbolTrades(ctx, registedAccountID, symbol, fromID)
+		if err != nil {
+			// Hợp đồng giao sau đã đáo hạn có thể không còn truy vấn được, không chặn các symbol còn lại.
+			// Lỗi vẫn được trả về để sync_state ghi nhận lần đồng bộ thất bại.
+			log.Printf("Failed to fetch Binance COIN-M trade history for user %s, symbol %s: %v", registedAccountID, symbol, err)
+			failed[symbol] = err
+			continue
+		}
+		orders = append(orders, symbolOrders...)
+	}
+	return orders, failed.Err()
+}
+
+// fetchSymbolTrades lấy các trade có ID >= fromID, phân trang theo fromId
+func (b *BinanceCoinMExchange) fetchSymbolTrades(ctx context.Context, registedAccountID primitive.ObjectID, symbol string, fromID int64) ([]models.Order, error) {
+	var orders []models.Order
+	for {
+		trades, err := b.listUserTrades(ctx, symbol, fromID, coinmTradeLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range trades {
+			orders = append(orders, coinmTradeToOrder(registedAccountID, trade))
+		}
+		if len(trades) < coinmTradeLimit {
+			break
+		}
+		fromID = trades[len(trades)-1].ID + 1
+	}
+	return orders, nil
+}
+
+// discoverSymbols trả về các symbol cần đồng bộ: symbol đã có cursor, symbol đang có vị thế
+// và các hợp đồng đang niêm yết có margin asset nằm trong số dư của tài khoản
+func (b *BinanceCoinMExchange) discoverSymbols(ctx context.Context, cursors exchanges.SyncCursors) ([]string, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	heldAssets := make(map[string]struct{})
+	for _, asset := range account.Assets {
+		if balance, err := decimal.NewFromString(asset.WalletBalance); err == nil && !balance.IsZero() {
+			heldAssets[asset.Asset] = struct{}{}
+		}
+	}
+	info, err := b.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	var symbols []string
+	add := func(symbol string) {
+		if _, ok := seen[symbol]; ok || symbol == "" {
+			return
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range cursors {
+		add(symbol)
+	}
+	for _, position := range account.Positions {
+		if amount, err := decimal.NewFromString(position.PositionAmt); err == nil && !amount.IsZero() {
+			add(position.Symbol)
+		}
+	}
+	for _, s := range info.Symbols {
+		if _, ok := heldAssets[s.MarginAsset]; ok {
+			add(s.Symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// coinmTrade là một trade trả về từ /dapi/v1/userTrades
+type coinmTrade struct {
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Symbol          string `json:"symbol"`
+	Pair            string `json:"pair"`
+	Side            string `json:"side"`
+	PositionSide    string `json:"positionSide"`
+	Price           string `json:"price"`
+	Quantity        string `json:"qty"`
+	BaseQuantity    string `json:"baseQty"`
+	RealizedPnl     string `json:"realizedPnl"`
+	MarginAsset     string `json:"marginAsset"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+	Buyer           bool   `json:"buyer"`
+	Maker           bool   `json:"maker"`
+}
+
+// listUserTrades gọi /dapi/v1/userTrades có ký. Thư viện delivery chưa có service cho endpoint này
+// nên request được ký bằng key và cấu hình của delivery client.
+func (b *BinanceCoinMExchange) listUserTrades(ctx context.Context, symbol string, fromID int64, limit int) ([]coinmTrade, error) {
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("fromId", strconv.FormatInt(fromID, 10))
+	query.Set("limit", strconv.Itoa(limit))
+	client := signedClient{
+		BaseURL:    b.client.BaseURL,
+		APIKey:     b.client.APIKey,
+		SecretKey:  b.client.SecretKey,
+		KeyType:    b.client.KeyType,
+		TimeOffset: b.client.TimeOffset,
+		HTTPClient: b.client.HTTPClient,
+	}
+	var trades []coinmTrade
+	if err := signedGet(ctx, client, "/dapi/v1/userTrades", query, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// coinmTradeToOrder chuyển trade COIN-M thành order. Quantity là số hợp đồng, QuoteQuantity là
+// giá trị danh nghĩa theo USD (baseQty x price), phí tính bằng margin asset (BTC, ETH, ...)
+func coinmTradeToOrder(registedAccountID primitive.ObjectID, trade coinmTrade) models.Order {
+	price := models.DecimalFromString(trade.Price)
+	baseQuantity := models.DecimalFromString(trade.BaseQuantity)
+	return models.Order{
+		ID:                  fmt.Sprintf("%d", trade.ID),
+		RegisteredAccountID: registedAccountID,
+		Symbol:              trade.Symbol,
+		OrderID:             trade.OrderID,
+		Price:               price,
+		Quantity:            models.DecimalFromString(trade.Quantity),
+		QuoteQuantity:       models.NewDecimal(baseQuantity.Mul(price.Decimal)),
+		Commission:          models.DecimalFromString(trade.Commission),
+		CommissionAsset:     trade.CommissionAsset,
+		Time:                time.UnixMilli(trade.Time),
+		Exchange:            "binance",
+		Market:              "coinm",
+		Side:                trade.Side,
+		PositionSide:        trade.PositionSide,
+	}
+}
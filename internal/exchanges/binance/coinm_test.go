@@ -0,0 +1,25 @@
+package binance
+
+import (
+	"autobackcom/internal/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCoinmTradeQuoteQuantityIsUSDNotional(t *testing.T) {
+	// 10 hợp đồng BTCUSD_PERP 100 USD ở giá 50000: baseQty = 10 x 100 / 50000 = 0.02 BTC
+	order := coinmTradeToOrder(primitive.NewObjectID(), coinmTrade{
+		ID:           1,
+		Symbol:       "BTCUSD_PERP",
+		Price:        "50000",
+		Quantity:     "10",
+		BaseQuantity: "0.02",
+	})
+	if !order.Quantity.Equal(models.DecimalFromString("10").Decimal) {
+		t.Errorf("quantity = %s, want 10 contracts", order.Quantity)
+	}
+	if !order.QuoteQuantity.Equal(models.DecimalFromString("1000").Decimal) {
+		t.Errorf("quote quantity = %s, want 1000 USD", order.QuoteQuantity)
+	}
+}
@@ -1,60 +1,259 @@
 package binance
 
 import (
+	"autobackcom/internal/exchanges"
 	"autobackcom/internal/models"
 	"context"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/adshao/go-binance/v2"
 	"github.com/adshao/go-binance/v2/futures"
+	"github.com/shopspring/decimal"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	// Số trade tối đa Binance trả về cho một lần gọi userTrades
+	futuresTradeLimit = 1000
+	// userTrades chỉ cho phép khoảng startTime/endTime tối đa 7 ngày
+	futuresTradeWindow = 7 * 24 * time.Hour
+	// Binance chỉ giữ lịch sử userTrades trong 6 tháng gần nhất
+	futuresHistoryLookback = 180 * 24 * time.Hour
+)
+
 type BinanceFeatureExchange struct {
 	client *futures.Client
+	// Client spot cùng key, chỉ dùng gọi apiRestrictions (nil với testnet)
+	spotClient *binance.Client
+	isTestnet  bool
 }
 
 func NewBinanceFetureExchange(apiKey, secret string, isTestnet bool) *BinanceFeatureExchange {
 	futures.UseTestnet = isTestnet
 	client := futures.NewClient(apiKey, secret)
-	return &BinanceFeatureExchange{client: client}
+	exchange := &BinanceFeatureExchange{client: client, isTestnet: isTestnet}
+	if !isTestnet {
+		exchange.spotClient = binance.NewClient(apiKey, secret)
+		exchange.spotClient.BaseURL = binance.BaseAPIMainURL
+	}
+	return exchange
+}
+
+// KeyPermissions đọc quyền của key qua apiRestrictions, với testnet chỉ kiểm tra key
+// bằng futures account info
+func (b *BinanceFeatureExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	if b.spotClient == nil {
+		account, err := b.client.NewGetAccountService().Do(ctx)
+		if err != nil {
+			return models.APIKeyPermissions{}, wrapKeyError(err)
+		}
+		return models.APIKeyPermissions{
+			Reading:        true,
+			FuturesTrading: account.CanTrade,
+			Withdrawals:    account.CanWithdraw,
+			CheckedAt:      time.Now(),
+		}, nil
+	}
+	restrictions, err := b.spotClient.NewGetAPIKeyPermission().Do(ctx)
+	if err != nil {
+		return models.APIKeyPermissions{}, wrapKeyError(err)
+	}
+	return permissionsFromRestrictions(restrictions), nil
 }
 
-func (b *BinanceFeatureExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, start time.Time) ([]models.Order, error) {
-	// Giả sử Client đã được khởi tạo với API key/secret đã giải mã từ user
-	svc := b.client.NewListAccountTradeService()
-	// Có thể cần truyền thêm symbol, startTime, endTime nếu muốn lọc theo thời gian
-	// svc.Symbol("BTCUSDT").StartTime(start.UnixMilli()).EndTime(end.UnixMilli())
-	if !start.IsZero() {
-		svc.StartTime(start.UnixMilli())
+// FetchTrades lấy trade futures theo từng symbol vì userTrades bắt buộc có symbol, mỗi symbol đi
+// theo cửa sổ thời gian từ mốc đã đồng bộ xong lần trước (hoặc trade cuối cùng đã lưu nếu chưa có mốc)
+func (b *BinanceFeatureExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	start := cursors.Market().SyncedUntil
+	if start.IsZero() {
+		for _, cursor := range cursors {
+			if cursor.LastTradeTime.After(start) {
+				start = cursor.LastTradeTime
+			}
+		}
+	}
+	now := time.Now()
+	if start.IsZero() || start.Before(now.Add(-futuresHistoryLookback)) {
+		start = now.Add(-futuresHistoryLookback)
 	}
-	svc.Limit(1000)
-	trades, err := svc.Do(ctx)
+	symbols, err := b.discoverSymbols(ctx, registedAccountID, cursors, start)
 	if err != nil {
-		log.Printf("Failed to fetch Binance features trade history for user %s: %v", registedAccountID, err)
+		log.Printf("Failed to discover Binance features symbols for user %s: %v", registedAccountID, err)
 		return nil, err
 	}
 	var orders []models.Order
-	for _, trade := range trades {
-
-		order := models.Order{
-			ID:                  fmt.Sprintf("%d", trade.ID),
-			RegisteredAccountID: registedAccountID,
-			Symbol:              trade.Symbol,
-			OrderID:             trade.OrderID,
-			Price:               trade.Price,
-			Quantity:            trade.Quantity,
-			QuoteQuantity:       trade.QuoteQuantity,
-			Commission:          trade.Commission,
-			CommissionAsset:     trade.CommissionAsset,
-			Time:                time.UnixMilli(trade.Time),
-			Exchange:            "binance",
-			Market:              "futures",
-			Side:                string(trade.Side),
-			PositionSide:        string(trade.PositionSide),
-		}
-		orders = append(orders, order)
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range symbols {
+		symbolOrders, err := b.fetchSymbolTrades(ctx, registedAccountID, symbol, start, now)
+		if err != nil {
+			log.Printf("Failed to fetch Binance features trade history for user %s, symbol %s: %v", registedAccountID, symbol, err)
+			failed[symbol] = err
+			continue
+		}
+		orders = append(orders, symbolOrders...)
+	}
+	return orders, failed.Err()
+}
+
+// discoverSymbols gom các symbol cần đồng bộ: symbol đã có cursor (gồm symbol của order đã lưu),
+// vị thế đang mở và symbol có income từ start (commission, PnL, funding) trong khoảng Binance còn giữ
+func (b *BinanceFeatureExchange) discoverSymbols(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors, start time.Time) ([]string, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	incomes, err := (&BinanceFuturesIncomeFetcher{client: b.client}).FetchIncomes(ctx, registedAccountID, start)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	var symbols []string
+	add := func(symbol string) {
+		if _, ok := seen[symbol]; ok || symbol == "" {
+			return
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range cursors {
+		add(symbol)
+	}
+	for _, position := range account.Positions {
+		if amount, err := decimal.NewFromString(position.PositionAmt); err == nil && !amount.IsZero() {
+			add(position.Symbol)
+		}
+	}
+	for _, income := range incomes {
+		add(income.Symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// fetchSymbolTrades đi lần lượt từng cửa sổ 7 ngày từ start tới now của một symbol
+func (b *BinanceFeatureExchange) fetchSymbolTrades(ctx context.Context, registedAccountID primitive.ObjectID, symbol string, start, now time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(futuresTradeWindow) {
+		windowEnd := windowStart.Add(futuresTradeWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		windowOrders, err := b.fetchWindow(ctx, registedAccountID, symbol, windowStart, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, windowOrders...)
+	}
+	return orders, nil
+}
+
+// fetchWindow lấy toàn bộ trade của symbol trong một cửa sổ thời gian, phân trang theo thời gian
+// vì userTrades không cho phép dùng fromId cùng với startTime/endTime, chỉ dùng fromId
+// khi một trang đầy trade trong cùng millisecond
+func (b *BinanceFeatureExchange) fetchWindow(ctx context.Context, registedAccountID primitive.ObjectID, symbol string, windowStart, windowEnd time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	seen := make(map[int64]struct{})
+	pageStart := windowStart.UnixMilli()
+	for {
+		trades, err := b.client.NewListAccountTradeService().
+			Symbol(symbol).
+			StartTime(pageStart).
+			EndTime(windowEnd.UnixMilli()).
+			Limit(futuresTradeLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range trades {
+			if _, ok := seen[trade.ID]; ok {
+				continue
+			}
+			seen[trade.ID] = struct{}{}
+			orders = append(orders, futuresTradeToOrder(registedAccountID, trade))
+		}
+		if len(trades) < futuresTradeLimit {
+			break
+		}
+		// Trang tiếp theo bắt đầu từ thời điểm trade cuối, trade trùng sẽ bị bỏ qua nhờ seen.
+		// Nếu cả trang cùng một millisecond thì lấy nốt trade của millisecond đó theo fromId
+		// rồi mới tiến sang millisecond sau để không bỏ sót trade.
+		next := trades[len(trades)-1].Time
+		if next <= pageStart {
+			rest, err := b.fetchMillisecond(ctx, symbol, trades[len(trades)-1].ID+1, pageStart)
+			if err != nil {
+				return nil, err
+			}
+			for _, trade := range rest {
+				if _, ok := seen[trade.ID]; ok {
+					continue
+				}
+				seen[trade.ID] = struct{}{}
+				orders = append(orders, futuresTradeToOrder(registedAccountID, trade))
+			}
+			next = pageStart + 1
+		}
+		pageStart = next
 	}
 	return orders, nil
 }
+
+// fetchMillisecond lấy các trade còn lại của symbol trong millisecond ms sau một trang đầy trade
+// cùng millisecond. fromId không dùng được cùng startTime/endTime nên đi tiếp từ fromID tới khi
+// sang millisecond sau.
+func (b *BinanceFeatureExchange) fetchMillisecond(ctx context.Context, symbol string, fromID, ms int64) ([]*futures.AccountTrade, error) {
+	var rest []*futures.AccountTrade
+	for {
+		trades, err := b.listUserTradesFromID(ctx, symbol, fromID)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range trades {
+			if trade.Time > ms {
+				return rest, nil
+			}
+			rest = append(rest, trade)
+			fromID = trade.ID + 1
+		}
+		if len(trades) < futuresTradeLimit {
+			return rest, nil
+		}
+	}
+}
+
+// listUserTradesFromID gọi /fapi/v1/userTrades theo fromId. Service của thư viện gửi tham số
+// "fromID" (sai tên) nên request được ký bằng key và cấu hình của futures client.
+func (b *BinanceFeatureExchange) listUserTradesFromID(ctx context.Context, symbol string, fromID int64) ([]*futures.AccountTrade, error) {
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("fromId", strconv.FormatInt(fromID, 10))
+	query.Set("limit", strconv.Itoa(futuresTradeLimit))
+	var trades []*futures.AccountTrade
+	if err := signedGet(ctx, futuresSignedClient(b.client), "/fapi/v1/userTrades", query, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func futuresTradeToOrder(registedAccountID primitive.ObjectID, trade *futures.AccountTrade) models.Order {
+	return models.Order{
+		ID:                  fmt.Sprintf("%d", trade.ID),
+		RegisteredAccountID: registedAccountID,
+		Symbol:              trade.Symbol,
+		OrderID:             trade.OrderID,
+		Price:               models.DecimalFromString(trade.Price),
+		Quantity:            models.DecimalFromString(trade.Quantity),
+		QuoteQuantity:       models.DecimalFromString(trade.QuoteQuantity),
+		Commission:          models.DecimalFromString(trade.Commission),
+		CommissionAsset:     trade.CommissionAsset,
+		Time:                time.UnixMilli(trade.Time),
+		Exchange:            "binance",
+		Market:              "futures",
+		Side:                string(trade.Side),
+		PositionSide:        string(trade.PositionSide),
+	}
+}
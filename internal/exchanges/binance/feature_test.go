@@ -0,0 +1,102 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestFeatureExchange tạo BinanceFeatureExchange trỏ tới mock server
+func newTestFeatureExchange(t *testing.T, handler http.HandlerFunc) *BinanceFeatureExchange {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	exchange := NewBinanceFetureExchange("key", "secret", false)
+	exchange.client.BaseURL = srv.URL
+	return exchange
+}
+
+func TestFeatureFetchTradesSendsSymbol(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	exchange := newTestFeatureExchange(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/account"):
+			json.NewEncoder(w).Encode(futures.Account{Positions: []*futures.AccountPosition{
+				{Symbol: "ETHUSDT", PositionAmt: "1.5"},
+				{Symbol: "XRPUSDT", PositionAmt: "0"},
+			}})
+		case r.URL.Path == "/fapi/v1/income":
+			json.NewEncoder(w).Encode([]futures.IncomeHistory{
+				{Symbol: "SOLUSDT", IncomeType: "COMMISSION", Asset: "USDT", Income: "-0.1", TranID: 1, Time: time.Now().UnixMilli()},
+				{Symbol: "", IncomeType: "TRANSFER", Asset: "USDT", Income: "10", TranID: 2, Time: time.Now().UnixMilli()},
+			})
+		case r.URL.Path == "/fapi/v1/userTrades":
+			symbol := r.URL.Query().Get("symbol")
+			if symbol == "" {
+				t.Errorf("userTrades request without symbol: %s", r.URL.RawQuery)
+			}
+			mu.Lock()
+			requested[symbol]++
+			first := requested[symbol] == 1
+			mu.Unlock()
+			trades := []futures.AccountTrade{}
+			if first {
+				trades = append(trades, futures.AccountTrade{ID: 1, Symbol: symbol, Price: "100", Quantity: "1", QuoteQuantity: "100", Time: time.Now().UnixMilli()})
+			}
+			json.NewEncoder(w).Encode(trades)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	cursors := exchanges.SyncCursors{
+		"BTCUSDT": {Symbol: "BTCUSDT", LastTradeTime: time.Now().Add(-time.Hour)},
+	}
+	orders, err := exchange.FetchTrades(context.Background(), primitive.NewObjectID(), cursors)
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+	var symbols []string
+	for symbol := range requested {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	if got, want := strings.Join(symbols, ","), "BTCUSDT,ETHUSDT,SOLUSDT"; got != want {
+		t.Errorf("requested symbols = %s, want %s", got, want)
+	}
+	if len(orders) != 3 {
+		t.Errorf("got %d orders, want 3", len(orders))
+	}
+}
+
+func TestFeatureQuoteQuantityIsUSDNotional(t *testing.T) {
+	trade := futuresTradeToOrder(primitive.NewObjectID(), &futures.AccountTrade{
+		ID:            1,
+		Symbol:        "BTCUSDT",
+		Price:         "50000",
+		Quantity:      "0.02",
+		QuoteQuantity: "1000",
+	})
+	execution := futuresExecutionToOrder(primitive.NewObjectID(), futures.WsOrderTradeUpdate{
+		TradeID:         1,
+		Symbol:          "BTCUSDT",
+		LastFilledPrice: "50000",
+		LastFilledQty:   "0.02",
+	})
+	for name, order := range map[string]models.Order{"userTrades": trade, "user stream": execution} {
+		if !order.QuoteQuantity.Equal(models.DecimalFromString("1000").Decimal) {
+			t.Errorf("%s quote quantity = %s, want 1000 USDT", name, order.QuoteQuantity)
+		}
+	}
+}
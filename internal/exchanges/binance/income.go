@@ -0,0 +1,142 @@
+package binance
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số income Binance trả về tối đa cho một lần gọi /fapi/v1/income
+	futuresIncomeLimit = 1000
+	// Chia khoảng thời gian thành từng cửa sổ 7 ngày như userTrades
+	futuresIncomeWindow = 7 * 24 * time.Hour
+	// Binance chỉ trả về income trong 3 tháng gần nhất
+	futuresIncomeLookback = 90 * 24 * time.Hour
+)
+
+// BinanceFuturesIncomeFetcher lấy lịch sử income của tài khoản USDⓈ-M futures
+type BinanceFuturesIncomeFetcher struct {
+	client *futures.Client
+}
+
+func NewBinanceFuturesIncomeFetcher(apiKey, secret string, isTestnet bool) *BinanceFuturesIncomeFetcher {
+	futures.UseTestnet = isTestnet
+	return &BinanceFuturesIncomeFetcher{client: futures.NewClient(apiKey, secret)}
+}
+
+// FetchIncomes lấy income từ since tới hiện tại, since rỗng hoặc quá cũ thì lấy 3 tháng gần nhất
+func (b *BinanceFuturesIncomeFetcher) FetchIncomes(ctx context.Context, registeredAccountID primitive.ObjectID, since time.Time) ([]models.Income, error) {
+	now := time.Now()
+	start := since
+	if start.IsZero() || start.Before(now.Add(-futuresIncomeLookback)) {
+		start = now.Add(-futuresIncomeLookback)
+	}
+	var incomes []models.Income
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(futuresIncomeWindow) {
+		windowEnd := windowStart.Add(futuresIncomeWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		windowIncomes, err := b.fetchWindow(ctx, registeredAccountID, windowStart, windowEnd)
+		if err != nil {
+			log.Printf("Failed to fetch Binance futures income history for account %s: %v", registeredAccountID.Hex(), err)
+			return nil, err
+		}
+		incomes = append(incomes, windowIncomes...)
+	}
+	return incomes, nil
+}
+
+// fetchWindow lấy toàn bộ income trong một cửa sổ, phân trang theo thời gian của bản ghi cuối
+func (b *BinanceFuturesIncomeFetcher) fetchWindow(ctx context.Context, registeredAccountID primitive.ObjectID, windowStart, windowEnd time.Time) ([]models.Income, error) {
+	var incomes []models.Income
+	seen := make(map[string]struct{})
+	pageStart := windowStart.UnixMilli()
+	for {
+		records, err := b.client.NewGetIncomeHistoryService().
+			StartTime(pageStart).
+			EndTime(windowEnd.UnixMilli()).
+			Limit(futuresIncomeLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			key := fmt.Sprintf("%d:%s:%s", record.TranID, record.IncomeType, record.Asset)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			incomes = append(incomes, futuresIncomeToModel(registeredAccountID, record))
+		}
+		if len(records) < futuresIncomeLimit {
+			break
+		}
+		// Trang tiếp theo bắt đầu từ bản ghi cuối, bản ghi trùng bị bỏ qua nhờ seen. Nếu cả trang
+		// cùng một millisecond thì lấy nốt income của millisecond đó theo tham số page rồi mới
+		// tiến sang millisecond sau để không bỏ sót income.
+		next := records[len(records)-1].Time
+		if next <= pageStart {
+			rest, err := b.fetchMillisecond(ctx, pageStart)
+			if err != nil {
+				return nil, err
+			}
+			for _, record := range rest {
+				key := fmt.Sprintf("%d:%s:%s", record.TranID, record.IncomeType, record.Asset)
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				incomes = append(incomes, futuresIncomeToModel(registeredAccountID, record))
+			}
+			next = pageStart + 1
+		}
+		pageStart = next
+	}
+	return incomes, nil
+}
+
+// fetchMillisecond lấy toàn bộ income trong millisecond ms bằng startTime = endTime = ms và đi
+// lần lượt theo tham số page (thư viện chưa hỗ trợ page nên request được ký bằng futures client)
+func (b *BinanceFuturesIncomeFetcher) fetchMillisecond(ctx context.Context, ms int64) ([]*futures.IncomeHistory, error) {
+	var records []*futures.IncomeHistory
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("startTime", strconv.FormatInt(ms, 10))
+		query.Set("endTime", strconv.FormatInt(ms, 10))
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(futuresIncomeLimit))
+		var pageRecords []*futures.IncomeHistory
+		if err := signedGet(ctx, futuresSignedClient(b.client), "/fapi/v1/income", query, &pageRecords); err != nil {
+			return nil, err
+		}
+		records = append(records, pageRecords...)
+		if len(pageRecords) < futuresIncomeLimit {
+			return records, nil
+		}
+	}
+}
+
+func futuresIncomeToModel(registeredAccountID primitive.ObjectID, record *futures.IncomeHistory) models.Income {
+	return models.Income{
+		RegisteredAccountID: registeredAccountID,
+		Exchange:            "binance",
+		Market:              "futures",
+		Symbol:              record.Symbol,
+		IncomeType:          record.IncomeType,
+		Income:              models.DecimalFromString(record.Income),
+		Asset:               record.Asset,
+		Info:                record.Info,
+		TranID:              record.TranID,
+		TradeID:             record.TradeID,
+		Time:                time.UnixMilli(record.Time),
+	}
+}
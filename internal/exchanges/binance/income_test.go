@@ -0,0 +1,54 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFetchIncomesDrainsFullMillisecond(t *testing.T) {
+	ms := time.Now().Add(-time.Hour).UnixMilli()
+	// 1500 income cùng millisecond ms, trang theo thời gian chỉ trả về 1000 bản ghi đầu
+	records := make([]futures.IncomeHistory, 1500)
+	for i := range records {
+		records[i] = futures.IncomeHistory{Symbol: "BTCUSDT", IncomeType: "COMMISSION", Asset: "USDT", Income: "-0.1", TranID: int64(i + 1), Time: ms}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		startTime, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		page, _ := strconv.Atoi(query.Get("page"))
+		switch {
+		case startTime > ms:
+			json.NewEncoder(w).Encode([]futures.IncomeHistory{})
+		case page == 0:
+			json.NewEncoder(w).Encode(records[:futuresIncomeLimit])
+		default:
+			start := (page - 1) * futuresIncomeLimit
+			end := start + futuresIncomeLimit
+			if start > len(records) {
+				start = len(records)
+			}
+			if end > len(records) {
+				end = len(records)
+			}
+			json.NewEncoder(w).Encode(records[start:end])
+		}
+	}))
+	t.Cleanup(srv.Close)
+	fetcher := NewBinanceFuturesIncomeFetcher("key", "secret", false)
+	fetcher.client.BaseURL = srv.URL
+	incomes, err := fetcher.FetchIncomes(context.Background(), primitive.NewObjectID(), time.UnixMilli(ms))
+	if err != nil {
+		t.Fatalf("FetchIncomes: %v", err)
+	}
+	if len(incomes) != len(records) {
+		t.Errorf("got %d incomes, want %d", len(incomes), len(records))
+	}
+}
@@ -0,0 +1,230 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số trade tối đa Binance trả về cho một lần gọi margin myTrades
+	marginTradeLimit = 1000
+	// Số bản ghi lãi tối đa mỗi trang của interestHistory
+	marginInterestPageSize = 100
+	// interestHistory chỉ cho phép khoảng startTime/endTime tối đa 30 ngày
+	marginInterestWindow = 30 * 24 * time.Hour
+	// Binance chỉ giữ lịch sử lãi margin trong 6 tháng gần nhất
+	marginHistoryLookback = 180 * 24 * time.Hour
+)
+
+// BinanceMarginExchange lấy trade và lãi vay của tài khoản margin cross hoặc isolated
+type BinanceMarginExchange struct {
+	client   *binance.Client
+	isolated bool
+}
+
+// NewBinanceMarginExchange tạo client margin, isolated=true cho market isolated_margin.
+// Margin không có trên testnet nên client luôn dùng mainnet.
+func NewBinanceMarginExchange(apiKey, secret string, isolated bool) *BinanceMarginExchange {
+	client := binance.NewClient(apiKey, secret)
+	client.BaseURL = binance.BaseAPIMainURL
+	return &BinanceMarginExchange{client: client, isolated: isolated}
+}
+
+func (b *BinanceMarginExchange) market() string {
+	if b.isolated {
+		return "isolated_margin"
+	}
+	return "margin"
+}
+
+// KeyPermissions đọc quyền của key qua apiRestrictions
+func (b *BinanceMarginExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	restrictions, err := b.client.NewGetAPIKeyPermission().Do(ctx)
+	if err != nil {
+		return models.APIKeyPermissions{}, wrapKeyError(err)
+	}
+	return permissionsFromRestrictions(restrictions), nil
+}
+
+// FetchTrades lấy trade margin theo từng symbol (phân trang theo fromId như spot) và lãi vay
+// phát sinh từ mốc đã đồng bộ xong lần trước. Lãi vay được lưu thành order có
+// Type = models.OrderTypeMarginInterest.
+func (b *BinanceMarginExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	symbols, err := b.discoverSymbols(ctx, cursors)
+	if err != nil {
+		log.Printf("Failed to discover Binance %s symbols for user %s: %v", b.market(), registedAccountID, err)
+		return nil, err
+	}
+	var orders []models.Order
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range symbols {
+		var fromID int64
+		if cursor, ok := cursors[symbol]; ok {
+			fromID = cursor.LastTradeID + 1
+		}
+		symbolOrders, err := b.fetchSymbolTrades(ctx, registedAccountID, symbol, fromID)
+		if err != nil {
+			log.Printf("Failed to fetch Binance %s trade history for user %s, symbol %s: %v", b.market(), registedAccountID, symbol, err)
+			failed[symbol] = err
+			continue
+		}
+		orders = append(orders, symbolOrders...)
+	}
+	interests, err := b.fetchInterests(ctx, registedAccountID, cursors.Market().SyncedUntil)
+	if err != nil {
+		log.Printf("Failed to fetch Binance %s interest history for user %s: %v", b.market(), registedAccountID, err)
+		return nil, err
+	}
+	return append(orders, interests...), failed.Err()
+}
+
+// fetchSymbolTrades lấy các trade có ID >= fromID, phân trang theo fromId
+func (b *BinanceMarginExchange) fetchSymbolTrades(ctx context.Context, registedAccountID primitive.ObjectID, symbol string, fromID int64) ([]models.Order, error) {
+	var orders []models.Order
+	for {
+		trades, err := b.client.NewListMarginTradesService().
+			Symbol(symbol).
+			IsIsolated(b.isolated).
+			FromID(fromID).
+			Limit(marginTradeLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range trades {
+			order := spotTradeToOrder(registedAccountID, trade)
+			order.Market = b.market()
+			orders = append(orders, order)
+		}
+		if len(trades) < marginTradeLimit {
+			break
+		}
+		fromID = trades[len(trades)-1].ID + 1
+	}
+	return orders, nil
+}
+
+// discoverSymbols trả về các symbol cần đồng bộ: symbol đã có cursor, với isolated là các cặp
+// đã mở tài khoản isolated, với cross là các cặp margin có base asset đang nằm trong tài khoản
+func (b *BinanceMarginExchange) discoverSymbols(ctx context.Context, cursors exchanges.SyncCursors) ([]string, error) {
+	seen := make(map[string]struct{})
+	var symbols []string
+	add := func(symbol string) {
+		if _, ok := seen[symbol]; ok || symbol == "" {
+			return
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range cursors {
+		add(symbol)
+	}
+	if b.isolated {
+		account, err := b.client.NewGetIsolatedMarginAccountService().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range account.Assets {
+			if asset.IsolatedCreated {
+				add(asset.Symbol)
+			}
+		}
+	} else {
+		account, err := b.client.NewGetMarginAccountService().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		heldAssets := make(map[string]struct{})
+		for _, asset := range account.UserAssets {
+			if nonZero(asset.Free) || nonZero(asset.Locked) || nonZero(asset.Borrowed) {
+				heldAssets[asset.Asset] = struct{}{}
+			}
+		}
+		pairs, err := b.client.NewGetMarginAllPairsService().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, pair := range pairs {
+			if _, ok := heldAssets[pair.Base]; ok {
+				add(pair.Symbol)
+			}
+		}
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// fetchInterests lấy lãi vay từ since tới hiện tại theo cửa sổ 30 ngày, mỗi cửa sổ phân trang theo current
+func (b *BinanceMarginExchange) fetchInterests(ctx context.Context, registedAccountID primitive.ObjectID, since time.Time) ([]models.Order, error) {
+	now := time.Now()
+	start := since
+	if start.IsZero() || start.Before(now.Add(-marginHistoryLookback)) {
+		start = now.Add(-marginHistoryLookback)
+	}
+	var orders []models.Order
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(marginInterestWindow) {
+		windowEnd := windowStart.Add(marginInterestWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		for page := int64(1); ; page++ {
+			service := b.client.NewMarginInterestHistoryService().
+				StartTime(windowStart.UnixMilli()).
+				EndTime(windowEnd.UnixMilli()).
+				Current(page).
+				Size(marginInterestPageSize)
+			history, err := service.Do(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, row := range history.Rows {
+				// interestHistory không lọc được theo loại tài khoản, chỉ giữ bản ghi đúng market
+				if (row.IsolatedSymbol != "") != b.isolated {
+					continue
+				}
+				orders = append(orders, b.interestToOrder(registedAccountID, row))
+			}
+			if len(history.Rows) < marginInterestPageSize {
+				break
+			}
+		}
+	}
+	return orders, nil
+}
+
+// interestToOrder chuyển một bản ghi lãi vay thành order: Commission là tiền lãi, Quantity là
+// số tiền gốc đang vay và Price là lãi suất. OrderID âm để không trùng order ID của trade thật.
+func (b *BinanceMarginExchange) interestToOrder(registedAccountID primitive.ObjectID, row binance.MarginInterestHistoryRow) models.Order {
+	symbol := row.IsolatedSymbol
+	if symbol == "" {
+		symbol = row.Asset
+	}
+	return models.Order{
+		ID:                  fmt.Sprintf("interest:%d", row.TxId),
+		RegisteredAccountID: registedAccountID,
+		Exchange:            "binance",
+		Market:              b.market(),
+		Symbol:              symbol,
+		Type:                models.OrderTypeMarginInterest,
+		OrderID:             -row.TxId,
+		Price:               models.DecimalFromString(row.InterestRate),
+		Quantity:            models.DecimalFromString(row.Principal),
+		Commission:          models.DecimalFromString(row.Interest),
+		CommissionAsset:     row.Asset,
+		Time:                time.UnixMilli(row.InterestAccuredTime),
+	}
+}
+
+func nonZero(amount string) bool {
+	d, err := decimal.NewFromString(amount)
+	return err == nil && !d.IsZero()
+}
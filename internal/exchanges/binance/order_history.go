@@ -0,0 +1,188 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Số order tối đa Binance trả về cho một lần gọi allOrders (spot và futures)
+const allOrdersLimit = 1000
+
+// FetchOrderHistory lấy order spot qua allOrders theo từng symbol: symbol có cursor, symbol có base
+// asset trong số dư (như đồng bộ trade) và symbol đang có order mở. allOrders trả về order có ID >= orderId nên order chưa kết thúc được lấy lại
+// ở lần sau nếu cursor dừng trước order đó.
+func (b *BinanceSpotExchange) FetchOrderHistory(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.OrderHistory, error) {
+	openOrders, err := b.client.NewListOpenOrdersService().Do(ctx)
+	if err != nil {
+		log.Printf("Failed to list Binance spot open orders for user %s: %v", registedAccountID, err)
+		return nil, err
+	}
+	openSymbols := make([]string, 0, len(openOrders))
+	for _, order := range openOrders {
+		openSymbols = append(openSymbols, order.Symbol)
+	}
+	symbols, err := b.discoverSymbols(ctx, cursors)
+	if err != nil {
+		log.Printf("Failed to discover Binance spot symbols for user %s: %v", registedAccountID, err)
+		return nil, err
+	}
+	var history []models.OrderHistory
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range orderHistorySymbols(symbols, openSymbols) {
+		fromID := cursors[symbol].LastTradeID + 1
+		for {
+			orders, err := b.client.NewListOrdersService().
+				Symbol(symbol).
+				OrderID(fromID).
+				Limit(allOrdersLimit).
+				Do(ctx)
+			if err != nil {
+				// Một symbol lỗi (ví dụ đã bị delist) không được chặn các symbol còn lại, order của các
+				// trang đã lấy vẫn được giữ vì allOrders trả về theo order ID tăng dần
+				log.Printf("Failed to fetch Binance spot orders for user %s, symbol %s: %v", registedAccountID, symbol, err)
+				failed[symbol] = err
+				break
+			}
+			for _, order := range orders {
+				history = append(history, spotOrderToHistory(registedAccountID, order))
+			}
+			if len(orders) < allOrdersLimit {
+				break
+			}
+			fromID = orders[len(orders)-1].OrderID + 1
+		}
+	}
+	return history, failed.Err()
+}
+
+// FetchOrderHistory lấy order USDⓈ-M futures qua allOrders theo từng symbol: symbol có cursor,
+// vị thế đang mở, symbol có income từ lần đồng bộ trước (như đồng bộ trade) và symbol có order mở.
+// Binance chỉ giữ order đã hủy/hết hạn không có fill trong thời gian ngắn nên cần đồng bộ thường xuyên.
+func (b *BinanceFeatureExchange) FetchOrderHistory(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.OrderHistory, error) {
+	openOrders, err := b.client.NewListOpenOrdersService().Do(ctx)
+	if err != nil {
+		log.Printf("Failed to list Binance futures open orders for user %s: %v", registedAccountID, err)
+		return nil, err
+	}
+	openSymbols := make([]string, 0, len(openOrders))
+	for _, order := range openOrders {
+		openSymbols = append(openSymbols, order.Symbol)
+	}
+	symbols, err := b.discoverSymbols(ctx, registedAccountID, cursors, cursors.Market().SyncedUntil)
+	if err != nil {
+		log.Printf("Failed to discover Binance features symbols for user %s: %v", registedAccountID, err)
+		return nil, err
+	}
+	var history []models.OrderHistory
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range orderHistorySymbols(symbols, openSymbols) {
+		fromID := cursors[symbol].LastTradeID + 1
+		for {
+			orders, err := b.client.NewListOrdersService().
+				Symbol(symbol).
+				OrderID(fromID).
+				Limit(allOrdersLimit).
+				Do(ctx)
+			if err != nil {
+				log.Printf("Failed to fetch Binance futures orders for user %s, symbol %s: %v", registedAccountID, symbol, err)
+				failed[symbol] = err
+				break
+			}
+			for _, order := range orders {
+				history = append(history, futuresOrderToHistory(registedAccountID, order))
+			}
+			if len(orders) < allOrdersLimit {
+				break
+			}
+			fromID = orders[len(orders)-1].OrderID + 1
+		}
+	}
+	return history, failed.Err()
+}
+
+// orderHistorySymbols gộp symbol tìm được như đồng bộ trade với symbol đang có order mở, theo thứ tự tên
+func orderHistorySymbols(discovered, openSymbols []string) []string {
+	seen := make(map[string]struct{})
+	var symbols []string
+	add := func(symbol string) {
+		if symbol == "" {
+			return
+		}
+		if _, ok := seen[symbol]; ok {
+			return
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+	for _, symbol := range discovered {
+		add(symbol)
+	}
+	for _, symbol := range openSymbols {
+		add(symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func spotOrderToHistory(registedAccountID primitive.ObjectID, order *binance.Order) models.OrderHistory {
+	executed := models.DecimalFromString(order.ExecutedQuantity)
+	quote := models.DecimalFromString(order.CummulativeQuoteQuantity)
+	var avgPrice models.Decimal
+	if !executed.IsZero() {
+		avgPrice = models.NewDecimal(quote.Div(executed.Decimal))
+	}
+	return models.OrderHistory{
+		RegisteredAccountID: registedAccountID,
+		Exchange:            "binance",
+		Market:              "spot",
+		Symbol:              order.Symbol,
+		OrderID:             order.OrderID,
+		ClientOrderID:       order.ClientOrderID,
+		OrderListId:         order.OrderListId,
+		Status:              string(order.Status),
+		Type:                string(order.Type),
+		Side:                string(order.Side),
+		TimeInForce:         string(order.TimeInForce),
+		Price:               models.DecimalFromString(order.Price),
+		StopPrice:           models.DecimalFromString(order.StopPrice),
+		Quantity:            models.DecimalFromString(order.OrigQuantity),
+		ExecutedQuantity:    executed,
+		QuoteQuantity:       quote,
+		AvgPrice:            avgPrice,
+		Time:                time.UnixMilli(order.Time),
+		UpdateTime:          time.UnixMilli(order.UpdateTime),
+	}
+}
+
+func futuresOrderToHistory(registedAccountID primitive.ObjectID, order *futures.Order) models.OrderHistory {
+	return models.OrderHistory{
+		RegisteredAccountID: registedAccountID,
+		Exchange:            "binance",
+		Market:              "futures",
+		Symbol:              order.Symbol,
+		OrderID:             order.OrderID,
+		ClientOrderID:       order.ClientOrderID,
+		Status:              string(order.Status),
+		Type:                string(order.Type),
+		Side:                string(order.Side),
+		PositionSide:        string(order.PositionSide),
+		TimeInForce:         string(order.TimeInForce),
+		Price:               models.DecimalFromString(order.Price),
+		StopPrice:           models.DecimalFromString(order.StopPrice),
+		Quantity:            models.DecimalFromString(order.OrigQuantity),
+		ExecutedQuantity:    models.DecimalFromString(order.ExecutedQuantity),
+		QuoteQuantity:       models.DecimalFromString(order.CumQuote),
+		AvgPrice:            models.DecimalFromString(order.AvgPrice),
+		ReduceOnly:          order.ReduceOnly,
+		Time:                time.UnixMilli(order.Time),
+		UpdateTime:          time.UnixMilli(order.UpdateTime),
+	}
+}
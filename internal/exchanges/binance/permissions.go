@@ -0,0 +1,44 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// Mã lỗi Binance trả về khi API key, chữ ký hoặc IP không hợp lệ
+var invalidKeyCodes = map[int64]bool{
+	-1022: true, // Signature for this request is not valid
+	-2008: true, // Invalid Api-Key ID
+	-2014: true, // API-key format invalid
+	-2015: true, // Invalid API-key, IP, or permissions for action
+}
+
+// wrapKeyError chuyển lỗi xác thực của Binance thành exchanges.ErrInvalidAPIKey
+func wrapKeyError(err error) error {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) && invalidKeyCodes[apiErr.Code] {
+		return fmt.Errorf("%w: %s", exchanges.ErrInvalidAPIKey, apiErr.Message)
+	}
+	return err
+}
+
+func permissionsFromRestrictions(p *binance.APIKeyPermission) models.APIKeyPermissions {
+	return models.APIKeyPermissions{
+		Reading:           p.EnableReading,
+		SpotTrading:       p.EnableSpotAndMarginTrading,
+		MarginTrading:     p.EnableMargin,
+		FuturesTrading:    p.EnableFutures,
+		OptionsTrading:    p.EnableVanillaOptions,
+		Withdrawals:       p.EnableWithdrawals,
+		InternalTransfer:  p.EnableInternalTransfer,
+		UniversalTransfer: p.PermitsUniversalTransfer,
+		IPRestricted:      p.IPRestrict,
+		CheckedAt:         time.Now(),
+	}
+}
@@ -0,0 +1,40 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoKline là lỗi không có nến tại thời điểm yêu cầu (symbol không tồn tại hoặc chưa niêm yết)
+var ErrNoKline = errors.New("no kline")
+
+// BinancePriceFeed lấy giá lịch sử từ kline public của Binance spot, không cần API key
+type BinancePriceFeed struct {
+	client *binance.Client
+}
+
+func NewBinancePriceFeed() *BinancePriceFeed {
+	return &BinancePriceFeed{client: binance.NewClient("", "")}
+}
+
+// CloseAt trả về giá đóng cửa của nến 1 phút chứa thời điểm at
+func (f *BinancePriceFeed) CloseAt(ctx context.Context, symbol string, at time.Time) (decimal.Decimal, error) {
+	minute := at.UTC().Truncate(time.Minute)
+	klines, err := f.client.NewKlinesService().
+		Symbol(symbol).
+		Interval("1m").
+		StartTime(minute.UnixMilli()).
+		Limit(1).
+		Do(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(klines) == 0 || klines[0].OpenTime != minute.UnixMilli() {
+		return decimal.Zero, ErrNoKline
+	}
+	return decimal.NewFromString(klines[0].Close)
+}
@@ -0,0 +1,79 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// signedClient là key và cấu hình ký request lấy từ client của thư viện (futures, delivery),
+// dùng cho endpoint thư viện chưa có service hoặc gửi sai tham số
+type signedClient struct {
+	BaseURL    string
+	APIKey     string
+	SecretKey  string
+	KeyType    string
+	TimeOffset int64
+	HTTPClient *http.Client
+}
+
+// signedGet gọi GET có ký tới path với query và decode response JSON vào out,
+// lỗi của Binance được trả về dưới dạng *common.APIError như thư viện
+func signedGet(ctx context.Context, c signedClient, path string, query url.Values, out interface{}) error {
+	query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli()-c.TimeOffset, 10))
+	keyType := c.KeyType
+	if keyType == "" {
+		keyType = common.KeyTypeHmac
+	}
+	sign, err := common.SignFunc(keyType)
+	if err != nil {
+		return err
+	}
+	signature, err := sign(c.SecretKey, query.Encode())
+	if err != nil {
+		return err
+	}
+	fullURL := fmt.Sprintf("%s%s?%s&signature=%s", c.BaseURL, path, query.Encode(), url.QueryEscape(*signature))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-MBX-APIKEY", c.APIKey)
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		apiErr := &common.APIError{}
+		if err := json.Unmarshal(data, apiErr); err != nil || !apiErr.IsValid() {
+			apiErr.Response = data
+		}
+		return apiErr
+	}
+	return json.Unmarshal(data, out)
+}
+
+// futuresSignedClient lấy key và cấu hình ký request của futures client
+func futuresSignedClient(client *futures.Client) signedClient {
+	return signedClient{
+		BaseURL:    client.BaseURL,
+		APIKey:     client.APIKey,
+		SecretKey:  client.SecretKey,
+		KeyType:    client.KeyType,
+		TimeOffset: client.TimeOffset,
+		HTTPClient: client.HTTPClient,
+	}
+}
@@ -1,59 +1,160 @@
 package binance
 
 import (
+	"autobackcom/internal/exchanges"
 	"autobackcom/internal/models"
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	// Số trade tối đa Binance trả về cho một lần gọi myTrades
+	spotTradeLimit = 1000
+)
+
 type BinanceSpotExchange struct {
-	client *binance.Client
+	client    *binance.Client
+	isTestnet bool
 }
 
 func NewBinanceSpotExchange(apiKey, secret string, isTestnet bool) *BinanceSpotExchange {
 	binance.UseTestnet = isTestnet
 	client := binance.NewClient(apiKey, secret)
-	return &BinanceSpotExchange{client: client}
+	return &BinanceSpotExchange{client: client, isTestnet: isTestnet}
 }
 
-func (b *BinanceSpotExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, start time.Time) ([]models.Order, error) {
-	// Giả sử Client đã được khởi tạo với API key/secret đã giải mã từ user
-	svc := b.client.NewListTradesService()
-	// Có thể cần truyền thêm symbol, startTime, endTime nếu muốn lọc theo thời gian
-	// svc.Symbol("BTCUSDT").StartTime(start.UnixMilli()).EndTime(end.UnixMilli())
-	if !start.IsZero() {
-		svc.StartTime(start.UnixMilli())
+// KeyPermissions đọc quyền của key qua apiRestrictions. Testnet không có endpoint sapi
+// nên chỉ kiểm tra key bằng account info.
+func (b *BinanceSpotExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	if b.isTestnet {
+		account, err := b.client.NewGetAccountService().Do(ctx)
+		if err != nil {
+			return models.APIKeyPermissions{}, wrapKeyError(err)
+		}
+		return models.APIKeyPermissions{
+			Reading:     true,
+			SpotTrading: account.CanTrade,
+			Withdrawals: account.CanWithdraw,
+			CheckedAt:   time.Now(),
+		}, nil
 	}
-	svc.Limit(1000)
-	trades, err := svc.Do(ctx)
+	restrictions, err := b.client.NewGetAPIKeyPermission().Do(ctx)
 	if err != nil {
-		log.Printf("Failed to fetch Binance spot trade history for user %s: %v", registedAccountID, err)
+		return models.APIKeyPermissions{}, wrapKeyError(err)
+	}
+	return permissionsFromRestrictions(restrictions), nil
+}
+
+// FetchTrades lấy trade spot theo từng symbol vì myTrades bắt buộc phải có symbol.
+// Symbol có cursor thì đi tiếp từ trade ID sau cursor, symbol chưa từng đồng bộ
+// thì lấy lại từ trade đầu tiên.
+func (b *BinanceSpotExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	symbols, err := b.discoverSymbols(ctx, cursors)
+	if err != nil {
+		log.Printf("Failed to discover Binance spot symbols for user %s: %v", registedAccountID, err)
 		return nil, err
 	}
 	var orders []models.Order
-	for _, trade := range trades {
-
-		order := models.Order{
-			ID:                  fmt.Sprintf("%d", trade.ID),
-			RegisteredAccountID: registedAccountID,
-			Symbol:              trade.Symbol,
-			OrderID:             trade.OrderID,
-			OrderListId:         trade.OrderListId,
-			Price:               trade.Price,
-			Quantity:            trade.Quantity,
-			QuoteQuantity:       trade.QuoteQuantity,
-			Commission:          trade.Commission,
-			CommissionAsset:     trade.CommissionAsset,
-			Time:                time.UnixMilli(trade.Time),
-			Exchange:            "binance",
-			Market:              "spot",
-		}
-		orders = append(orders, order)
+	failed := exchanges.SymbolErrors{}
+	for _, symbol := range symbols {
+		var fromID int64
+		if cursor, ok := cursors[symbol]; ok {
+			fromID = cursor.LastTradeID + 1
+		}
+		symbolOrders, err := b.fetchSymbolTrades(ctx, registedAccountID, symbol, fromID)
+		if err != nil {
+			// Một symbol lỗi (ví dụ đã bị delist) không được chặn các symbol còn lại,
+			// lỗi vẫn được trả về để sync_state ghi nhận lần đồng bộ thất bại
+			log.Printf("Failed to fetch Binance spot trade history for user %s, symbol %s: %v", registedAccountID, symbol, err)
+			failed[symbol] = err
+			continue
+		}
+		orders = append(orders, symbolOrders...)
+	}
+	return orders, failed.Err()
+}
+
+// fetchSymbolTrades lấy các trade có ID >= fromID, phân trang theo fromId
+func (b *BinanceSpotExchange) fetchSymbolTrades(ctx context.Context, registedAccountID primitive.ObjectID, symbol string, fromID int64) ([]models.Order, error) {
+	var orders []models.Order
+	for {
+		trades, err := b.client.NewListTradesService().
+			Symbol(symbol).
+			FromID(fromID).
+			Limit(spotTradeLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range trades {
+			orders = append(orders, spotTradeToOrder(registedAccountID, trade))
+		}
+		if len(trades) < spotTradeLimit {
+			break
+		}
+		fromID = trades[len(trades)-1].ID + 1
 	}
 	return orders, nil
 }
+
+// discoverSymbols trả về các symbol cần đồng bộ: symbol đã có cursor (gồm cả symbol đã có order
+// được service thêm vào) và các symbol có base asset đang nằm trong số dư của tài khoản
+func (b *BinanceSpotExchange) discoverSymbols(ctx context.Context, cursors exchanges.SyncCursors) ([]string, error) {
+	account, err := b.client.NewGetAccountService().OmitZeroBalances(true).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	heldAssets := make(map[string]struct{}, len(account.Balances))
+	for _, balance := range account.Balances {
+		heldAssets[balance.Asset] = struct{}{}
+	}
+	info, err := b.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	var symbols []string
+	for symbol := range cursors {
+		if symbol == "" {
+			continue
+		}
+		seen[symbol] = struct{}{}
+		symbols = append(symbols, symbol)
+	}
+	for _, s := range info.Symbols {
+		if _, ok := heldAssets[s.BaseAsset]; !ok {
+			continue
+		}
+		if _, ok := seen[s.Symbol]; ok {
+			continue
+		}
+		seen[s.Symbol] = struct{}{}
+		symbols = append(symbols, s.Symbol)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+func spotTradeToOrder(registedAccountID primitive.ObjectID, trade *binance.TradeV3) models.Order {
+	return models.Order{
+		ID:                  fmt.Sprintf("%d", trade.ID),
+		RegisteredAccountID: registedAccountID,
+		Symbol:              trade.Symbol,
+		OrderID:             trade.OrderID,
+		OrderListId:         trade.OrderListId,
+		Price:               models.DecimalFromString(trade.Price),
+		Quantity:            models.DecimalFromString(trade.Quantity),
+		QuoteQuantity:       models.DecimalFromString(trade.QuoteQuantity),
+		Commission:          models.DecimalFromString(trade.Commission),
+		CommissionAsset:     trade.CommissionAsset,
+		Time:                time.UnixMilli(trade.Time),
+		Exchange:            "binance",
+		Market:              "spot",
+	}
+}
@@ -0,0 +1,31 @@
+package binance
+
+import (
+	"autobackcom/internal/models"
+	"testing"
+
+	"github.com/adshao/go-binance/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSpotQuoteQuantityIsQuoteAssetValue(t *testing.T) {
+	trade := spotTradeToOrder(primitive.NewObjectID(), &binance.TradeV3{
+		ID:            1,
+		Symbol:        "BTCUSDT",
+		Price:         "50000",
+		Quantity:      "0.02",
+		QuoteQuantity: "1000",
+	})
+	execution := spotExecutionToOrder(primitive.NewObjectID(), binance.WsOrderUpdate{
+		TradeId:           1,
+		Symbol:            "BTCUSDT",
+		LatestPrice:       "50000",
+		LatestVolume:      "0.02",
+		LatestQuoteVolume: "1000",
+	})
+	for name, order := range map[string]models.Order{"userTrades": trade, "user stream": execution} {
+		if !order.QuoteQuantity.Equal(models.DecimalFromString("1000").Decimal) {
+			t.Errorf("%s quote quantity = %s, want 1000 USDT", name, order.QuoteQuantity)
+		}
+	}
+}
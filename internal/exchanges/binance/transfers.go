@@ -0,0 +1,232 @@
+package binance
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số bản ghi tối đa mỗi trang của lịch sử nạp/rút
+	walletHistoryLimit = 1000
+	// Lịch sử nạp/rút chỉ cho phép khoảng startTime/endTime tối đa 90 ngày
+	walletHistoryWindow = 90 * 24 * time.Hour
+	// Số bản ghi tối đa mỗi trang của /sapi/v1/asset/transfer
+	universalTransferPageSize = 100
+	// Chia lịch sử chuyển tiền nội bộ thành từng cửa sổ 30 ngày
+	universalTransferWindow = 30 * 24 * time.Hour
+	// Binance chỉ trả về lịch sử chuyển tiền nội bộ trong 6 tháng gần nhất, nạp/rút dùng cùng mốc
+	transferLookback = 180 * 24 * time.Hour
+	// Lấy lại các bản ghi trong 7 ngày trước mốc đã đồng bộ để cập nhật trạng thái nạp/rút đang chờ
+	transferRescanWindow = 7 * 24 * time.Hour
+	// Layout thời gian applyTime/completeTime của lịch sử rút (UTC)
+	withdrawTimeLayout = "2006-01-02 15:04:05"
+)
+
+// Các loại chuyển tiền nội bộ giữa ví spot, funding, margin cross và futures. Chuyển tiền với
+// isolated margin bắt buộc truyền symbol nên không được liệt kê.
+var universalTransferTypes = []binance.UserUniversalTransferType{
+	binance.UserUniversalTransferTypeMainToUmFutures,
+	binance.UserUniversalTransferTypeMainToCmFutures,
+	binance.UserUniversalTransferTypeMainToMargin,
+	binance.UserUniversalTransferTypeMainToFunding,
+	binance.UserUniversalTransferTypeUmFuturesToMain,
+	binance.UserUniversalTransferTypeUmFuturesToMargin,
+	binance.UserUniversalTransferTypeUmFuturesToFunding,
+	binance.UserUniversalTransferTypeCmFuturesToMain,
+	binance.UserUniversalTransferTypeCmFuturesToMargin,
+	binance.UserUniversalTransferTypeCmFuturesToFunding,
+	binance.UserUniversalTransferTypeMarginToMain,
+	binance.UserUniversalTransferTypeMarginToUmFutures,
+	binance.UserUniversalTransferTypeMarginToCmFutures,
+	binance.UserUniversalTransferTypeMarginToFunding,
+	binance.UserUniversalTransferTypeFundingToMain,
+	binance.UserUniversalTransferTypeFundingToUmFutures,
+	binance.UserUniversalTransferTypeFundingToCmFutures,
+	binance.UserUniversalTransferTypeFundingToMargin,
+}
+
+// BinanceTransferFetcher lấy lịch sử nạp, rút và chuyển tiền nội bộ của tài khoản. Lịch sử ví
+// là chung cho mọi market của cùng API key và chỉ có trên mainnet.
+type BinanceTransferFetcher struct {
+	client *binance.Client
+}
+
+func NewBinanceTransferFetcher(apiKey, secret string) *BinanceTransferFetcher {
+	client := binance.NewClient(apiKey, secret)
+	client.BaseURL = binance.BaseAPIMainURL
+	return &BinanceTransferFetcher{client: client}
+}
+
+// FetchTransfers lấy nạp, rút và chuyển tiền nội bộ từ 7 ngày trước since tới hiện tại,
+// since rỗng hoặc quá cũ thì lấy 6 tháng gần nhất
+func (b *BinanceTransferFetcher) FetchTransfers(ctx context.Context, registeredAccountID primitive.ObjectID, since time.Time) ([]models.Transfer, error) {
+	now := time.Now()
+	start := since.Add(-transferRescanWindow)
+	if since.IsZero() || start.Before(now.Add(-transferLookback)) {
+		start = now.Add(-transferLookback)
+	}
+	var transfers []models.Transfer
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(walletHistoryWindow) {
+		windowEnd := windowStart.Add(walletHistoryWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		deposits, err := b.fetchDeposits(ctx, registeredAccountID, windowStart, windowEnd)
+		if err != nil {
+			log.Printf("Failed to fetch Binance deposit history for account %s: %v", registeredAccountID.Hex(), err)
+			return nil, err
+		}
+		withdrawals, err := b.fetchWithdrawals(ctx, registeredAccountID, windowStart, windowEnd)
+		if err != nil {
+			log.Printf("Failed to fetch Binance withdrawal history for account %s: %v", registeredAccountID.Hex(), err)
+			return nil, err
+		}
+		transfers = append(transfers, deposits...)
+		transfers = append(transfers, withdrawals...)
+	}
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(universalTransferWindow) {
+		windowEnd := windowStart.Add(universalTransferWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		for _, transferType := range universalTransferTypes {
+			windowTransfers, err := b.fetchUniversalTransfers(ctx, registeredAccountID, transferType, windowStart, windowEnd)
+			if err != nil {
+				log.Printf("Failed to fetch Binance %s transfer history for account %s: %v", transferType, registeredAccountID.Hex(), err)
+				return nil, err
+			}
+			transfers = append(transfers, windowTransfers...)
+		}
+	}
+	return transfers, nil
+}
+
+// fetchDeposits lấy lịch sử nạp trong một cửa sổ, phân trang theo offset
+func (b *BinanceTransferFetcher) fetchDeposits(ctx context.Context, registeredAccountID primitive.ObjectID, windowStart, windowEnd time.Time) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	for offset := 0; ; offset += walletHistoryLimit {
+		records, err := b.client.NewListDepositsService().
+			StartTime(windowStart.UnixMilli()).
+			EndTime(windowEnd.UnixMilli()).
+			Offset(offset).
+			Limit(walletHistoryLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			transfers = append(transfers, depositToModel(registeredAccountID, record))
+		}
+		if len(records) < walletHistoryLimit {
+			return transfers, nil
+		}
+	}
+}
+
+// fetchWithdrawals lấy lịch sử rút trong một cửa sổ, phân trang theo offset
+func (b *BinanceTransferFetcher) fetchWithdrawals(ctx context.Context, registeredAccountID primitive.ObjectID, windowStart, windowEnd time.Time) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	for offset := 0; ; offset += walletHistoryLimit {
+		records, err := b.client.NewListWithdrawsService().
+			StartTime(windowStart.UnixMilli()).
+			EndTime(windowEnd.UnixMilli()).
+			Offset(offset).
+			Limit(walletHistoryLimit).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			transfers = append(transfers, withdrawToModel(registeredAccountID, record))
+		}
+		if len(records) < walletHistoryLimit {
+			return transfers, nil
+		}
+	}
+}
+
+// fetchUniversalTransfers lấy chuyển tiền nội bộ của một loại trong một cửa sổ, phân trang theo current
+func (b *BinanceTransferFetcher) fetchUniversalTransfers(ctx context.Context, registeredAccountID primitive.ObjectID, transferType binance.UserUniversalTransferType, windowStart, windowEnd time.Time) ([]models.Transfer, error) {
+	var transfers []models.Transfer
+	for current := 1; ; current++ {
+		res, err := b.client.NewListUserUniversalTransferService().
+			Type(transferType).
+			StartTime(windowStart.UnixMilli()).
+			EndTime(windowEnd.UnixMilli()).
+			Current(current).
+			Size(universalTransferPageSize).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range res.Results {
+			transfers = append(transfers, universalTransferToModel(registeredAccountID, record))
+		}
+		if len(res.Results) < universalTransferPageSize || int64(current*universalTransferPageSize) >= res.Total {
+			return transfers, nil
+		}
+	}
+}
+
+// depositToModel chuyển bản ghi nạp sang Transfer. Thư viện không trả về id của deposit nên
+// khóa được ghép từ txId, coin và thời điểm ghi nhận (không đổi khi trạng thái thay đổi).
+func depositToModel(registeredAccountID primitive.ObjectID, record *binance.Deposit) models.Transfer {
+	return models.Transfer{
+		RegisteredAccountID: registeredAccountID,
+		Exchange:            "binance",
+		Type:                models.TransferTypeDeposit,
+		TransferID:          fmt.Sprintf("%s:%s:%d", record.TxID, record.Coin, record.InsertTime),
+		Asset:               record.Coin,
+		Amount:              models.DecimalFromString(record.Amount),
+		Network:             record.Network,
+		Address:             record.Address,
+		TxID:                record.TxID,
+		Status:              strconv.Itoa(record.Status),
+		Time:                time.UnixMilli(record.InsertTime),
+	}
+}
+
+func withdrawToModel(registeredAccountID primitive.ObjectID, record *binance.Withdraw) models.Transfer {
+	appliedAt, err := time.ParseInLocation(withdrawTimeLayout, record.ApplyTime, time.UTC)
+	if err != nil {
+		log.Printf("Invalid Binance withdrawal apply time %q for withdrawal %s: %v", record.ApplyTime, record.ID, err)
+	}
+	return models.Transfer{
+		RegisteredAccountID: registeredAccountID,
+		Exchange:            "binance",
+		Type:                models.TransferTypeWithdrawal,
+		TransferID:          record.ID,
+		Asset:               record.Coin,
+		Amount:              models.DecimalFromString(record.Amount),
+		Fee:                 models.DecimalFromString(record.TransactionFee),
+		Network:             record.Network,
+		Address:             record.Address,
+		TxID:                record.TxID,
+		Status:              strconv.Itoa(record.Status),
+		Time:                appliedAt,
+	}
+}
+
+func universalTransferToModel(registeredAccountID primitive.ObjectID, record *binance.UserUniversalTransfer) models.Transfer {
+	from, to, _ := strings.Cut(string(record.Type), "_")
+	return models.Transfer{
+		RegisteredAccountID: registeredAccountID,
+		Exchange:            "binance",
+		Type:                models.TransferTypeTransfer,
+		TransferID:          strconv.FormatInt(record.TranId, 10),
+		Asset:               record.Asset,
+		Amount:              models.DecimalFromString(record.Amount),
+		Status:              string(record.Status),
+		FromAccount:         from,
+		ToAccount:           to,
+		Time:                time.UnixMilli(record.Timestamp),
+	}
+}
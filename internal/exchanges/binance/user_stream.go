@@ -0,0 +1,118 @@
+package binance
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Loại execution của fill trong executionReport/ORDER_TRADE_UPDATE
+	tradeExecutionType = "TRADE"
+	// Sự kiện spot gửi khi listen key hết hạn, go-binance chưa có hằng số cho spot
+	spotListenKeyExpiredEvent = "listenKeyExpired"
+)
+
+func (b *BinanceSpotExchange) StartUserStream(ctx context.Context) (string, error) {
+	return b.client.NewStartUserStreamService().Do(ctx)
+}
+
+func (b *BinanceSpotExchange) KeepaliveUserStream(ctx context.Context, listenKey string) error {
+	return b.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+}
+
+func (b *BinanceSpotExchange) CloseUserStream(ctx context.Context, listenKey string) error {
+	return b.client.NewCloseUserStreamService().ListenKey(listenKey).Do(ctx)
+}
+
+// ServeUserStream chuyển executionReport có execution TRADE thành order giống myTrades
+// để upsert trùng khóa với fill lấy qua REST
+func (b *BinanceSpotExchange) ServeUserStream(registedAccountID primitive.ObjectID, listenKey string, onTrade func(models.Order), onError func(error)) (chan struct{}, chan struct{}, error) {
+	// Endpoint websocket đọc theo biến toàn cục như các constructor
+	binance.UseTestnet = b.isTestnet
+	return binance.WsUserDataServe(listenKey, func(event *binance.WsUserDataEvent) {
+		switch event.Event {
+		case spotListenKeyExpiredEvent:
+			onError(exchanges.ErrListenKeyExpired)
+		case binance.UserDataEventTypeExecutionReport:
+			if event.OrderUpdate.ExecutionType != tradeExecutionType {
+				return
+			}
+			onTrade(spotExecutionToOrder(registedAccountID, event.OrderUpdate))
+		}
+	}, onError)
+}
+
+func spotExecutionToOrder(registedAccountID primitive.ObjectID, update binance.WsOrderUpdate) models.Order {
+	return models.Order{
+		ID:                  fmt.Sprintf("%d", update.TradeId),
+		RegisteredAccountID: registedAccountID,
+		Symbol:              update.Symbol,
+		OrderID:             update.Id,
+		OrderListId:         update.OrderListId,
+		Price:               models.DecimalFromString(update.LatestPrice),
+		Quantity:            models.DecimalFromString(update.LatestVolume),
+		QuoteQuantity:       models.DecimalFromString(update.LatestQuoteVolume),
+		Commission:          models.DecimalFromString(update.FeeCost),
+		CommissionAsset:     update.FeeAsset,
+		Time:                time.UnixMilli(update.TransactionTime),
+		Exchange:            "binance",
+		Market:              "spot",
+	}
+}
+
+func (b *BinanceFeatureExchange) StartUserStream(ctx context.Context) (string, error) {
+	return b.client.NewStartUserStreamService().Do(ctx)
+}
+
+func (b *BinanceFeatureExchange) KeepaliveUserStream(ctx context.Context, listenKey string) error {
+	return b.client.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+}
+
+func (b *BinanceFeatureExchange) CloseUserStream(ctx context.Context, listenKey string) error {
+	return b.client.NewCloseUserStreamService().ListenKey(listenKey).Do(ctx)
+}
+
+// ServeUserStream chuyển ORDER_TRADE_UPDATE có execution TRADE thành order giống userTrades,
+// listenKeyExpired được báo qua onError bằng exchanges.ErrListenKeyExpired
+func (b *BinanceFeatureExchange) ServeUserStream(registedAccountID primitive.ObjectID, listenKey string, onTrade func(models.Order), onError func(error)) (chan struct{}, chan struct{}, error) {
+	futures.UseTestnet = b.isTestnet
+	return futures.WsUserDataServe(listenKey, func(event *futures.WsUserDataEvent) {
+		switch event.Event {
+		case futures.UserDataEventTypeListenKeyExpired:
+			onError(exchanges.ErrListenKeyExpired)
+		case futures.UserDataEventTypeOrderTradeUpdate:
+			if event.OrderTradeUpdate.ExecutionType != futures.OrderExecutionTypeTrade {
+				return
+			}
+			onTrade(futuresExecutionToOrder(registedAccountID, event.OrderTradeUpdate))
+		}
+	}, onError)
+}
+
+func futuresExecutionToOrder(registedAccountID primitive.ObjectID, update futures.WsOrderTradeUpdate) models.Order {
+	price := models.DecimalFromString(update.LastFilledPrice)
+	quantity := models.DecimalFromString(update.LastFilledQty)
+	return models.Order{
+		ID:                  fmt.Sprintf("%d", update.TradeID),
+		RegisteredAccountID: registedAccountID,
+		Symbol:              update.Symbol,
+		OrderID:             update.ID,
+		Price:               price,
+		Quantity:            quantity,
+		QuoteQuantity:       models.NewDecimal(price.Mul(quantity.Decimal)),
+		Commission:          models.DecimalFromString(update.Commission),
+		CommissionAsset:     update.CommissionAsset,
+		Time:                time.UnixMilli(update.TradeTime),
+		Exchange:            "binance",
+		Market:              "futures",
+		Side:                string(update.Side),
+		PositionSide:        string(update.PositionSide),
+	}
+}
@@ -0,0 +1,17 @@
+package bybit
+
+import "autobackcom/internal/exchanges"
+
+// Adapter mô tả các market Bybit cho exchanges.Registry
+func Adapter() exchanges.Adapter {
+	return exchanges.Adapter{
+		Exchange: "bybit",
+		Markets: []exchanges.Market{
+			{Name: "spot", SupportsTestnet: true},
+			{Name: "linear", SupportsTestnet: true},
+		},
+		New: func(market string, creds exchanges.Credentials, isTestnet bool) (exchanges.ExchangeFetcher, error) {
+			return NewBybitExchange(market, creds.APIKey, creds.Secret, isTestnet)
+		},
+	}
+}
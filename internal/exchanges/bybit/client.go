@@ -0,0 +1,166 @@
+package bybit
+
+import (
+	"autobackcom/internal/exchanges"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	mainnetBaseURL = "https://api.bybit.com"
+	testnetBaseURL = "https://api-testnet.bybit.com"
+	recvWindow     = "5000"
+	// Mã lỗi vượt rate limit, chờ tới thời điểm reset rồi gọi lại
+	retCodeRateLimited = 10006
+	// Số lần gọi lại tối đa khi bị rate limit
+	maxRateLimitRetries = 3
+)
+
+// Mã lỗi Bybit trả về khi API key, chữ ký hoặc IP không hợp lệ
+var invalidKeyCodes = map[int]bool{
+	10003: true, // API key không hợp lệ
+	10004: true, // Sai chữ ký
+	10005: true, // Key không có quyền với endpoint
+	10010: true, // IP không nằm trong whitelist
+	33004: true, // API key đã hết hạn
+}
+
+// APIError là lỗi Bybit trả về trong retCode/retMsg của response
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bybit api error: retCode=%d, retMsg=%s", e.Code, e.Message)
+}
+
+// Client gọi Bybit v5 REST API có ký HMAC
+type Client struct {
+	APIKey     string
+	Secret     string
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient tạo client, BYBIT_BASE_URL (nếu có) được dùng thay cho URL mainnet/testnet
+func NewClient(apiKey, secret string, isTestnet bool) *Client {
+	baseURL := os.Getenv("BYBIT_BASE_URL")
+	if baseURL == "" {
+		baseURL = mainnetBaseURL
+		if isTestnet {
+			baseURL = testnetBaseURL
+		}
+	}
+	return &Client{
+		APIKey:     apiKey,
+		Secret:     secret,
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// sign tính X-BAPI-SIGN = hex(HMAC-SHA256(secret, timestamp + apiKey + recvWindow + queryString))
+func (c *Client) sign(timestamp, queryString string) string {
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write([]byte(timestamp + c.APIKey + recvWindow + queryString))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// get gọi GET có ký và decode trường result của response vào out. Khi quota còn lại theo
+// header X-Bapi-Limit-Status đã hết hoặc bị trả về lỗi rate limit thì chờ tới
+// X-Bapi-Limit-Reset-Timestamp trước khi gọi tiếp.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		retCode, err := c.doGet(ctx, path, query, out)
+		if retCode != retCodeRateLimited || attempt >= maxRateLimitRetries {
+			return err
+		}
+	}
+}
+
+func (c *Client) doGet(ctx context.Context, path string, query url.Values, out interface{}) (int, error) {
+	queryString := query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path+"?"+queryString, nil)
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("X-BAPI-API-KEY", c.APIKey)
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	req.Header.Set("X-BAPI-SIGN", c.sign(timestamp, queryString))
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	var envelope struct {
+		RetCode int             `json:"retCode"`
+		RetMsg  string          `json:"retMsg"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		if res.StatusCode == http.StatusUnauthorized {
+			return 0, fmt.Errorf("%w: http status %d", exchanges.ErrInvalidAPIKey, res.StatusCode)
+		}
+		return 0, fmt.Errorf("bybit response status %d: %s", res.StatusCode, string(data))
+	}
+	if envelope.RetCode == retCodeRateLimited || remainingQuota(res.Header) == 0 {
+		if err := waitForReset(ctx, res.Header); err != nil {
+			return 0, err
+		}
+	}
+	if envelope.RetCode != 0 {
+		apiErr := &APIError{Code: envelope.RetCode, Message: envelope.RetMsg}
+		if invalidKeyCodes[apiErr.Code] {
+			return envelope.RetCode, fmt.Errorf("%w: %s", exchanges.ErrInvalidAPIKey, apiErr.Message)
+		}
+		return envelope.RetCode, apiErr
+	}
+	return 0, json.Unmarshal(envelope.Result, out)
+}
+
+// remainingQuota đọc số request còn lại trong cửa sổ rate limit, -1 nếu không có header
+func remainingQuota(header http.Header) int {
+	remaining, err := strconv.Atoi(header.Get("X-Bapi-Limit-Status"))
+	if err != nil {
+		return -1
+	}
+	return remaining
+}
+
+// waitForReset chờ tới thời điểm reset rate limit, tối đa 5 giây nếu header không hợp lệ
+func waitForReset(ctx context.Context, header http.Header) error {
+	wait := 5 * time.Second
+	if resetAt, err := strconv.ParseInt(header.Get("X-Bapi-Limit-Reset-Timestamp"), 10, 64); err == nil {
+		if until := time.Until(time.UnixMilli(resetAt)); until < wait {
+			wait = until
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+	log.Printf("Bybit rate limit reached, waiting %s", wait)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
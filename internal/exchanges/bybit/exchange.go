@@ -0,0 +1,206 @@
+package bybit
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số execution tối đa Bybit trả về cho một lần gọi execution list
+	executionLimit = 100
+	// execution list chỉ cho phép khoảng startTime/endTime tối đa 7 ngày
+	executionWindow = 7 * 24 * time.Hour
+	// Đồng bộ lần đầu lấy 6 tháng gần nhất như Binance
+	executionLookback = 180 * 24 * time.Hour
+)
+
+// categories là category Bybit v5 tương ứng với market đã đăng ký
+var categories = map[string]string{
+	"spot":   "spot",
+	"linear": "linear",
+}
+
+// BybitExchange lấy execution spot hoặc linear (USDT/USDC perpetual) của tài khoản Bybit
+type BybitExchange struct {
+	client   *Client
+	market   string
+	category string
+}
+
+func NewBybitExchange(market, apiKey, secret string, isTestnet bool) (*BybitExchange, error) {
+	category, ok := categories[market]
+	if !ok {
+		return nil, fmt.Errorf("unsupported market: %s for exchange: bybit", market)
+	}
+	return &BybitExchange{
+		client:   NewClient(apiKey, secret, isTestnet),
+		market:   market,
+		category: category,
+	}, nil
+}
+
+// KeyPermissions đọc quyền của key qua /v5/user/query-api
+func (b *BybitExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	var info struct {
+		ReadOnly    int                 `json:"readOnly"`
+		IPs         []string            `json:"ips"`
+		Permissions map[string][]string `json:"permissions"`
+	}
+	if err := b.client.get(ctx, "/v5/user/query-api", url.Values{}, &info); err != nil {
+		return models.APIKeyPermissions{}, err
+	}
+	permissions := models.APIKeyPermissions{
+		Reading:   true,
+		CheckedAt: time.Now(),
+	}
+	for _, ip := range info.IPs {
+		if ip != "" && ip != "*" {
+			permissions.IPRestricted = true
+		}
+	}
+	if info.ReadOnly == 1 {
+		return permissions, nil
+	}
+	permissions.SpotTrading = len(info.Permissions["Spot"]) > 0
+	permissions.FuturesTrading = len(info.Permissions["ContractTrade"]) > 0 || len(info.Permissions["Derivatives"]) > 0
+	permissions.OptionsTrading = len(info.Permissions["Options"]) > 0
+	for _, perm := range info.Permissions["Wallet"] {
+		switch perm {
+		case "AccountTransfer":
+			permissions.InternalTransfer = true
+		case "SubMemberTransfer":
+			permissions.UniversalTransfer = true
+		case "Withdraw":
+			permissions.Withdrawals = true
+		}
+	}
+	return permissions, nil
+}
+
+// bybitExecution là một execution trả về từ /v5/execution/list
+type bybitExecution struct {
+	Symbol      string `json:"symbol"`
+	OrderID     string `json:"orderId"`
+	Side        string `json:"side"`
+	OrderType   string `json:"orderType"`
+	ExecID      string `json:"execId"`
+	ExecPrice   string `json:"execPrice"`
+	ExecQty     string `json:"execQty"`
+	ExecValue   string `json:"execValue"`
+	ExecFee     string `json:"execFee"`
+	ExecType    string `json:"execType"`
+	ExecTime    string `json:"execTime"`
+	FeeCurrency string `json:"feeCurrency"`
+}
+
+// FetchTrades lấy execution theo từng cửa sổ 7 ngày từ mốc đã đồng bộ xong lần trước,
+// mỗi cửa sổ phân trang bằng nextPageCursor. execId của Bybit không phải số nên không
+// có cursor theo symbol, chỉ dùng mốc thời gian của cả market.
+func (b *BybitExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	now := time.Now()
+	start := cursors.Market().SyncedUntil
+	if start.IsZero() || start.Before(now.Add(-executionLookback)) {
+		start = now.Add(-executionLookback)
+	}
+	var orders []models.Order
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(executionWindow) {
+		windowEnd := windowStart.Add(executionWindow - time.Millisecond)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+		windowOrders, err := b.fetchWindow(ctx, registedAccountID, windowStart, windowEnd)
+		if err != nil {
+			log.Printf("Failed to fetch Bybit %s executions for user %s: %v", b.market, registedAccountID, err)
+			return nil, err
+		}
+		orders = append(orders, windowOrders...)
+	}
+	return orders, nil
+}
+
+func (b *BybitExchange) fetchWindow(ctx context.Context, registedAccountID primitive.ObjectID, windowStart, windowEnd time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("category", b.category)
+		query.Set("startTime", strconv.FormatInt(windowStart.UnixMilli(), 10))
+		query.Set("endTime", strconv.FormatInt(windowEnd.UnixMilli(), 10))
+		query.Set("limit", strconv.Itoa(executionLimit))
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var result struct {
+			NextPageCursor string           `json:"nextPageCursor"`
+			List           []bybitExecution `json:"list"`
+		}
+		if err := b.client.get(ctx, "/v5/execution/list", query, &result); err != nil {
+			return nil, err
+		}
+		for _, execution := range result.List {
+			// Funding cũng nằm trong execution list nhưng không phải trade
+			if execution.ExecType == "Funding" {
+				continue
+			}
+			orders = append(orders, b.executionToOrder(registedAccountID, execution))
+		}
+		if result.NextPageCursor == "" || len(result.List) == 0 {
+			return orders, nil
+		}
+		cursor = result.NextPageCursor
+	}
+}
+
+func (b *BybitExchange) executionToOrder(registedAccountID primitive.ObjectID, execution bybitExecution) models.Order {
+	execTime, _ := strconv.ParseInt(execution.ExecTime, 10, 64)
+	feeCurrency := execution.FeeCurrency
+	if feeCurrency == "" {
+		feeCurrency = settleCoin(execution.Symbol)
+	}
+	return models.Order{
+		ID:                  execution.ExecID,
+		RegisteredAccountID: registedAccountID,
+		Exchange:            "bybit",
+		Market:              b.market,
+		Symbol:              execution.Symbol,
+		Type:                strings.ToUpper(execution.OrderType),
+		OrderID:             orderIDToInt(execution.OrderID),
+		Side:                strings.ToUpper(execution.Side),
+		Price:               models.DecimalFromString(execution.ExecPrice),
+		Quantity:            models.DecimalFromString(execution.ExecQty),
+		QuoteQuantity:       models.DecimalFromString(execution.ExecValue),
+		Commission:          models.DecimalFromString(execution.ExecFee),
+		CommissionAsset:     feeCurrency,
+		Time:                time.UnixMilli(execTime),
+	}
+}
+
+// orderIDToInt đổi orderId của Bybit (số với spot, UUID với linear) sang int64 vì order_id
+// đang là số; UUID được băm FNV-64a nên cùng một order luôn cho cùng một giá trị
+func orderIDToInt(orderID string) int64 {
+	if id, err := strconv.ParseInt(orderID, 10, 64); err == nil {
+		return id
+	}
+	h := fnv.New64a()
+	h.Write([]byte(orderID))
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+// settleCoin đoán coin thanh toán phí của hợp đồng linear: USDC perpetual có hậu tố PERP
+// hoặc USDC, còn lại là USDT
+func settleCoin(symbol string) string {
+	if strings.HasSuffix(symbol, "PERP") || strings.HasSuffix(symbol, "USDC") {
+		return "USDC"
+	}
+	return "USDT"
+}
@@ -0,0 +1,28 @@
+package bybit
+
+import (
+	"autobackcom/internal/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExecutionQuoteQuantityIsUSDNotional(t *testing.T) {
+	tests := []struct {
+		market    string
+		execution bybitExecution
+	}{
+		{market: "spot", execution: bybitExecution{Symbol: "BTCUSDT", ExecID: "1", ExecPrice: "50000", ExecQty: "0.02", ExecValue: "1000", ExecTime: "1700000000000"}},
+		{market: "linear", execution: bybitExecution{Symbol: "BTCUSDT", ExecID: "2", ExecPrice: "50000", ExecQty: "0.02", ExecValue: "1000", ExecTime: "1700000000000"}},
+	}
+	for _, tt := range tests {
+		exchange, err := NewBybitExchange(tt.market, "key", "secret", false)
+		if err != nil {
+			t.Fatalf("NewBybitExchange: %v", err)
+		}
+		order := exchange.executionToOrder(primitive.NewObjectID(), tt.execution)
+		if !order.QuoteQuantity.Equal(models.DecimalFromString("1000").Decimal) {
+			t.Errorf("%s quote quantity = %s, want 1000 USDT", tt.market, order.QuoteQuantity)
+		}
+	}
+}
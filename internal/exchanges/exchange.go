@@ -3,11 +3,98 @@ package exchanges
 import (
 	"autobackcom/internal/models"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// SyncCursors là trạng thái đồng bộ theo symbol, key rỗng là trạng thái của cả market
+type SyncCursors map[string]models.SyncState
+
+// Market trả về trạng thái đồng bộ của cả market
+func (c SyncCursors) Market() models.SyncState {
+	return c[""]
+}
+
+// ErrInvalidAPIKey là lỗi exchange từ chối API key (sai key, sai chữ ký, bị chặn IP)
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// ErrListenKeyExpired là lỗi listen key của user data stream hết hạn, cần tạo key mới và kết nối lại
+var ErrListenKeyExpired = errors.New("listen key expired")
+
+// SymbolErrors là lỗi của các symbol không đồng bộ được, fetcher vẫn trả về dữ liệu của các symbol
+// còn lại cùng lỗi này để cursor của symbol thành công vẫn được lưu
+type SymbolErrors map[string]error
+
+func (e SymbolErrors) Error() string {
+	symbols := make([]string, 0, len(e))
+	for symbol := range e {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	messages := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		messages[i] = fmt.Sprintf("%s: %v", symbol, e[symbol])
+	}
+	return fmt.Sprintf("%d symbol(s) failed: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Err trả về nil nếu không có symbol lỗi
+func (e SymbolErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
 type ExchangeFetcher interface {
-	FetchTrades(ctx context.Context, userID primitive.ObjectID, start time.Time) ([]models.Order, error)
+	FetchTrades(ctx context.Context, userID primitive.ObjectID, cursors SyncCursors) ([]models.Order, error)
+	// KeyPermissions gọi API chỉ đọc có ký để kiểm tra key và lấy quyền của key
+	KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error)
+}
+
+// MarketCursorFetcher là fetcher đi theo một cursor chung cho cả market thay vì cursor theo symbol
+// (billId của OKX tăng dần trên toàn tài khoản). LastTradeID của trạng thái market (symbol rỗng)
+// chỉ được lưu sau khi toàn bộ trade của lần đồng bộ đã được lưu.
+type MarketCursorFetcher interface {
+	UsesMarketCursor() bool
+}
+
+// IncomeFetcher lấy lịch sử income (PnL đã chốt, funding, phí, ...) của tài khoản futures
+// từ mốc since tới hiện tại
+type IncomeFetcher interface {
+	FetchIncomes(ctx context.Context, registeredAccountID primitive.ObjectID, since time.Time) ([]models.Income, error)
+}
+
+// OrderHistoryFetcher lấy vòng đời order (kể cả order bị hủy/hết hạn) theo symbol. Symbol có
+// cursor thì lấy từ order ID sau LastTradeID, symbol chưa có cursor thì lấy từ order đầu tiên.
+// Symbol lỗi được trả về trong SymbolErrors cùng order của các symbol còn lại.
+type OrderHistoryFetcher interface {
+	FetchOrderHistory(ctx context.Context, registeredAccountID primitive.ObjectID, cursors SyncCursors) ([]models.OrderHistory, error)
+}
+
+// TransferFetcher lấy lịch sử nạp, rút và chuyển tiền nội bộ của tài khoản từ mốc since tới hiện tại.
+// Bản ghi gần since có thể được trả về lại để cập nhật trạng thái (pending -> success).
+type TransferFetcher interface {
+	FetchTransfers(ctx context.Context, registeredAccountID primitive.ObjectID, since time.Time) ([]models.Transfer, error)
+}
+
+// BalanceFetcher đọc số dư hiện tại của tài khoản theo từng asset
+type BalanceFetcher interface {
+	FetchBalances(ctx context.Context) ([]models.AssetBalance, error)
+}
+
+// UserStreamer mở user data stream để nhận fill theo thời gian thực. Listen key phải được
+// keepalive định kỳ, stream có thể bị ngắt bất cứ lúc nào nên fill vẫn phải được lấy lại qua REST.
+type UserStreamer interface {
+	StartUserStream(ctx context.Context) (listenKey string, err error)
+	KeepaliveUserStream(ctx context.Context, listenKey string) error
+	CloseUserStream(ctx context.Context, listenKey string) error
+	// ServeUserStream kết nối websocket, gọi onTrade với mỗi fill mới và onError khi có lỗi
+	// (ErrListenKeyExpired khi key hết hạn). doneC đóng khi kết nối kết thúc, đóng stopC để ngắt kết nối.
+	ServeUserStream(registeredAccountID primitive.ObjectID, listenKey string, onTrade func(models.Order), onError func(error)) (doneC, stopC chan struct{}, err error)
 }
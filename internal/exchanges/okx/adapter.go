@@ -0,0 +1,18 @@
+package okx
+
+import "autobackcom/internal/exchanges"
+
+// Adapter mô tả các market OKX cho exchanges.Registry, key OKX luôn cần passphrase
+func Adapter() exchanges.Adapter {
+	return exchanges.Adapter{
+		Exchange: "okx",
+		Markets: []exchanges.Market{
+			{Name: "spot", SupportsTestnet: true},
+			{Name: "swap", SupportsTestnet: true},
+		},
+		RequiresPassphrase: true,
+		New: func(market string, creds exchanges.Credentials, isTestnet bool) (exchanges.ExchangeFetcher, error) {
+			return NewOKXExchange(market, creds.APIKey, creds.Secret, creds.Passphrase, isTestnet)
+		},
+	}
+}
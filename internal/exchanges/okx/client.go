@@ -0,0 +1,122 @@
+package okx
+
+import (
+	"autobackcom/internal/exchanges"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// BaseURL mặc định của OKX REST API, đổi bằng OKX_BASE_URL (ví dụ trỏ tới mock server)
+const defaultBaseURL = "https://www.okx.com"
+
+// Mã lỗi OKX trả về khi API key, passphrase, chữ ký hoặc IP không hợp lệ
+var invalidKeyCodes = map[string]bool{
+	"50101": true, // API key không khớp môi trường (demo/live)
+	"50105": true, // Passphrase sai
+	"50110": true, // IP không nằm trong whitelist
+	"50111": true, // OK-ACCESS-KEY không hợp lệ
+	"50113": true, // Chữ ký không hợp lệ
+	"50114": true, // Authorization không hợp lệ
+}
+
+// APIError là lỗi OKX trả về trong trường code/msg của response
+type APIError struct {
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("okx api error: code=%s, msg=%s", e.Code, e.Message)
+}
+
+// Client gọi OKX REST API có ký bằng API key, secret và passphrase
+type Client struct {
+	APIKey     string
+	Secret     string
+	Passphrase string
+	BaseURL    string
+	// Simulated bật header x-simulated-trading để dùng key demo trading
+	Simulated  bool
+	HTTPClient *http.Client
+}
+
+func NewClient(apiKey, secret, passphrase string, simulated bool) *Client {
+	baseURL := os.Getenv("OKX_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		APIKey:     apiKey,
+		Secret:     secret,
+		Passphrase: passphrase,
+		BaseURL:    baseURL,
+		Simulated:  simulated,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// sign tính OK-ACCESS-SIGN = base64(HMAC-SHA256(secret, timestamp + method + requestPath + body))
+func (c *Client) sign(timestamp, method, requestPath, body string) string {
+	mac := hmac.New(sha256.New, []byte(c.Secret))
+	mac.Write([]byte(timestamp + method + requestPath + body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// get gọi GET có ký và decode trường data của response vào out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	requestPath := path
+	if len(query) > 0 {
+		requestPath += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+requestPath, nil)
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	req.Header.Set("OK-ACCESS-KEY", c.APIKey)
+	req.Header.Set("OK-ACCESS-SIGN", c.sign(timestamp, http.MethodGet, requestPath, ""))
+	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("OK-ACCESS-PASSPHRASE", c.Passphrase)
+	req.Header.Set("Content-Type", "application/json")
+	if c.Simulated {
+		req.Header.Set("x-simulated-trading", "1")
+	}
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	var envelope struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("okx response status %d: %s", res.StatusCode, string(data))
+	}
+	if envelope.Code != "0" {
+		return wrapKeyError(&APIError{Code: envelope.Code, Message: envelope.Msg})
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// wrapKeyError chuyển lỗi xác thực của OKX thành exchanges.ErrInvalidAPIKey
+func wrapKeyError(err *APIError) error {
+	if invalidKeyCodes[err.Code] {
+		return fmt.Errorf("%w: %s", exchanges.ErrInvalidAPIKey, err.Message)
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+package okx
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Số fill tối đa OKX trả về cho một lần gọi fills-history
+	fillsHistoryLimit = 100
+	// fills-history giới hạn 10 request / 2 giây, nghỉ giữa các trang để không bị chặn
+	fillsHistoryPause = 200 * time.Millisecond
+)
+
+// instTypes là loại sản phẩm OKX tương ứng với market đã đăng ký
+var instTypes = map[string]string{
+	"spot": "SPOT",
+	"swap": "SWAP",
+}
+
+// OKXExchange lấy fill spot hoặc perpetual swap của tài khoản OKX
+type OKXExchange struct {
+	client   *Client
+	market   string
+	instType string
+	// contracts là thông tin hợp đồng swap theo instId, nạp khi đồng bộ fill swap
+	contracts map[string]okxInstrument
+}
+
+func NewOKXExchange(market, apiKey, secret, passphrase string, isTestnet bool) (*OKXExchange, error) {
+	instType, ok := instTypes[market]
+	if !ok {
+		return nil, fmt.Errorf("unsupported market: %s for exchange: okx", market)
+	}
+	return &OKXExchange{
+		client:   NewClient(apiKey, secret, passphrase, isTestnet),
+		market:   market,
+		instType: instType,
+	}, nil
+}
+
+// KeyPermissions đọc quyền của key qua account config (perm gồm read_only, trade, withdraw)
+func (o *OKXExchange) KeyPermissions(ctx context.Context) (models.APIKeyPermissions, error) {
+	var configs []struct {
+		Perm string `json:"perm"`
+		IP   string `json:"ip"`
+	}
+	if err := o.client.get(ctx, "/api/v5/account/config", nil, &configs); err != nil {
+		return models.APIKeyPermissions{}, err
+	}
+	if len(configs) == 0 {
+		return models.APIKeyPermissions{}, fmt.Errorf("okx account config is empty")
+	}
+	permissions := models.APIKeyPermissions{
+		IPRestricted: configs[0].IP != "",
+		CheckedAt:    time.Now(),
+	}
+	for _, perm := range strings.Split(configs[0].Perm, ",") {
+		switch strings.TrimSpace(perm) {
+		case "read_only":
+			permissions.Reading = true
+		case "trade":
+			permissions.SpotTrading = true
+			permissions.MarginTrading = true
+			permissions.FuturesTrading = true
+			permissions.OptionsTrading = true
+		case "withdraw":
+			permissions.Withdrawals = true
+		}
+	}
+	return permissions, nil
+}
+
+// okxFill là một fill trả về từ /api/v5/trade/fills-history
+type okxFill struct {
+	InstType string `json:"instType"`
+	InstID   string `json:"instId"`
+	TradeID  string `json:"tradeId"`
+	OrdID    string `json:"ordId"`
+	BillID   string `json:"billId"`
+	FillPx   string `json:"fillPx"`
+	FillSz   string `json:"fillSz"`
+	Side     string `json:"side"`
+	PosSide  string `json:"posSide"`
+	ExecType string `json:"execType"`
+	FeeCcy   string `json:"feeCcy"`
+	Fee      string `json:"fee"`
+	Ts       string `json:"ts"`
+}
+
+// okxInstrument là thông tin hợp đồng trả về từ /api/v5/public/instruments
+type okxInstrument struct {
+	InstID string `json:"instId"`
+	CtVal  string `json:"ctVal"`
+	CtType string `json:"ctType"`
+}
+
+// loadContracts lấy contract value của mọi hợp đồng swap để quy đổi số hợp đồng sang giá trị quote
+func (o *OKXExchange) loadContracts(ctx context.Context) error {
+	query := url.Values{}
+	query.Set("instType", o.instType)
+	var instruments []okxInstrument
+	if err := o.client.get(ctx, "/api/v5/public/instruments", query, &instruments); err != nil {
+		return err
+	}
+	o.contracts = make(map[string]okxInstrument, len(instruments))
+	for _, instrument := range instruments {
+		o.contracts[instrument.InstID] = instrument
+	}
+	return nil
+}
+
+// UsesMarketCursor báo cho service lưu billId lớn nhất vào trạng thái market sau khi lưu đủ fill
+func (o *OKXExchange) UsesMarketCursor() bool {
+	return true
+}
+
+// FetchTrades lấy fill mới hơn billId của trạng thái market. fills-history trả về fill mới nhất trước,
+// nên đi lùi theo after=<billId> tới khi gặp fill đã lưu hoặc hết dữ liệu (OKX giữ 3 tháng).
+// Order.ID là billId vì billId tăng dần trên cả tài khoản, còn tradeId chỉ duy nhất trong một instrument.
+func (o *OKXExchange) FetchTrades(ctx context.Context, registedAccountID primitive.ObjectID, cursors exchanges.SyncCursors) ([]models.Order, error) {
+	lastBillID := cursors.Market().LastTradeID
+	if lastBillID == 0 {
+		// Account đồng bộ từ trước khi có cursor market chỉ có cursor theo symbol
+		for symbol, cursor := range cursors {
+			if symbol != "" && cursor.LastTradeID > lastBillID {
+				lastBillID = cursor.LastTradeID
+			}
+		}
+	}
+	if o.instType == "SWAP" {
+		if err := o.loadContracts(ctx); err != nil {
+			log.Printf("Failed to fetch OKX %s instruments for user %s: %v", o.market, registedAccountID, err)
+			return nil, err
+		}
+	}
+	var orders []models.Order
+	after := ""
+	for {
+		query := url.Values{}
+		query.Set("instType", o.instType)
+		query.Set("limit", strconv.Itoa(fillsHistoryLimit))
+		if after != "" {
+			query.Set("after", after)
+		}
+		var fills []okxFill
+		if err := o.client.get(ctx, "/api/v5/trade/fills-history", query, &fills); err != nil {
+			log.Printf("Failed to fetch OKX %s fills for user %s: %v", o.market, registedAccountID, err)
+			return nil, err
+		}
+		for _, fill := range fills {
+			billID, err := strconv.ParseInt(fill.BillID, 10, 64)
+			if err != nil {
+				continue
+			}
+			if billID <= lastBillID {
+				return orders, nil
+			}
+			orders = append(orders, o.fillToOrder(registedAccountID, fill))
+		}
+		if len(fills) < fillsHistoryLimit {
+			return orders, nil
+		}
+		after = fills[len(fills)-1].BillID
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(fillsHistoryPause):
+		}
+	}
+}
+
+// fillToOrder chuyển fill OKX thành order. OKX trả phí âm khi bị trừ và dương khi được hoàn,
+// Commission lưu theo quy ước của Binance (dương là phí phải trả).
+func (o *OKXExchange) fillToOrder(registedAccountID primitive.ObjectID, fill okxFill) models.Order {
+	price := models.DecimalFromString(fill.FillPx)
+	quantity := models.DecimalFromString(fill.FillSz)
+	fee := models.DecimalFromString(fill.Fee)
+	orderID, _ := strconv.ParseInt(fill.OrdID, 10, 64)
+	ts, _ := strconv.ParseInt(fill.Ts, 10, 64)
+	order := models.Order{
+		ID:                  fill.BillID,
+		RegisteredAccountID: registedAccountID,
+		Exchange:            "okx",
+		Market:              o.market,
+		Symbol:              fill.InstID,
+		OrderID:             orderID,
+		Side:                strings.ToUpper(fill.Side),
+		Price:               price,
+		Quantity:            quantity,
+		Commission:          models.NewDecimal(fee.Neg()),
+		CommissionAsset:     fill.FeeCcy,
+		Time:                time.UnixMilli(ts),
+	}
+	if fill.PosSide != "" && fill.PosSide != "net" {
+		order.PositionSide = strings.ToUpper(fill.PosSide)
+	}
+	// Với spot fillSz là số base asset nên giá trị quote là price x size. Swap tính theo số hợp đồng:
+	// hợp đồng linear có ctVal theo base asset nên giá trị là price x size x ctVal, hợp đồng inverse
+	// có ctVal theo USD nên giá trị là size x ctVal. Không có thông tin hợp đồng thì để trống.
+	switch o.instType {
+	case "SPOT":
+		order.QuoteQuantity = models.NewDecimal(price.Mul(quantity.Decimal))
+	case "SWAP":
+		if contract, ok := o.contracts[fill.InstID]; ok {
+			ctVal := models.DecimalFromString(contract.CtVal)
+			if contract.CtType == "inverse" {
+				order.QuoteQuantity = models.NewDecimal(quantity.Mul(ctVal.Decimal))
+			} else {
+				order.QuoteQuantity = models.NewDecimal(price.Mul(quantity.Decimal).Mul(ctVal.Decimal))
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,213 @@
+package okx
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestExchange tạo OKXExchange trỏ tới mock server
+func newTestExchange(t *testing.T, market string, handler http.HandlerFunc) *OKXExchange {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	exchange, err := NewOKXExchange(market, "key", "secret", "passphrase", false)
+	if err != nil {
+		t.Fatalf("NewOKXExchange: %v", err)
+	}
+	exchange.client.BaseURL = srv.URL
+	return exchange
+}
+
+func writeOK(w http.ResponseWriter, data interface{}) {
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": "0", "msg": "", "data": data})
+}
+
+func testFill(billID int64, instID string) okxFill {
+	id := strconv.FormatInt(billID, 10)
+	return okxFill{
+		InstType: "SPOT",
+		InstID:   instID,
+		TradeID:  id,
+		OrdID:    "1",
+		BillID:   id,
+		FillPx:   "100",
+		FillSz:   "2",
+		Side:     "buy",
+		FeeCcy:   "USDT",
+		Fee:      "-0.2",
+		Ts:       "1700000000000",
+	}
+}
+
+func TestClientSignsRequest(t *testing.T) {
+	exchange := newTestExchange(t, "spot", func(w http.ResponseWriter, r *http.Request) {
+		timestamp := r.Header.Get("OK-ACCESS-TIMESTAMP")
+		if timestamp == "" {
+			t.Error("missing OK-ACCESS-TIMESTAMP")
+		}
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(timestamp + http.MethodGet + r.URL.RequestURI()))
+		expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if got := r.Header.Get("OK-ACCESS-SIGN"); got != expected {
+			t.Errorf("OK-ACCESS-SIGN = %q, want %q", got, expected)
+		}
+		if got := r.Header.Get("OK-ACCESS-KEY"); got != "key" {
+			t.Errorf("OK-ACCESS-KEY = %q, want %q", got, "key")
+		}
+		if got := r.Header.Get("OK-ACCESS-PASSPHRASE"); got != "passphrase" {
+			t.Errorf("OK-ACCESS-PASSPHRASE = %q, want %q", got, "passphrase")
+		}
+		writeOK(w, []okxFill{})
+	})
+	if _, err := exchange.FetchTrades(context.Background(), primitive.NewObjectID(), exchanges.SyncCursors{}); err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+}
+
+func TestFetchTradesPagesUntilStoredBill(t *testing.T) {
+	var mu sync.Mutex
+	var afters []string
+	exchange := newTestExchange(t, "spot", func(w http.ResponseWriter, r *http.Request) {
+		after := r.URL.Query().Get("after")
+		mu.Lock()
+		afters = append(afters, after)
+		mu.Unlock()
+		newest := int64(300)
+		if after != "" {
+			parsed, _ := strconv.ParseInt(after, 10, 64)
+			newest = parsed - 1
+		}
+		fills := make([]okxFill, 0, fillsHistoryLimit)
+		for billID := newest; billID > newest-fillsHistoryLimit && billID > 0; billID-- {
+			fills = append(fills, testFill(billID, "BTC-USDT"))
+		}
+		writeOK(w, fills)
+	})
+	cursors := exchanges.SyncCursors{
+		"BTC-USDT": {Symbol: "BTC-USDT", LastTradeID: 150},
+	}
+	orders, err := exchange.FetchTrades(context.Background(), primitive.NewObjectID(), cursors)
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+	if len(orders) != 150 {
+		t.Fatalf("got %d orders, want 150", len(orders))
+	}
+	if orders[0].ID != "300" || orders[len(orders)-1].ID != "151" {
+		t.Errorf("orders span %s..%s, want 300..151", orders[0].ID, orders[len(orders)-1].ID)
+	}
+	if len(afters) != 2 || afters[0] != "" || afters[1] != "201" {
+		t.Errorf("after params = %q, want [\"\" \"201\"]", afters)
+	}
+}
+
+func TestFetchTradesUsesMarketCursor(t *testing.T) {
+	exchange := newTestExchange(t, "spot", func(w http.ResponseWriter, r *http.Request) {
+		fills := make([]okxFill, 0, 100)
+		for billID := int64(300); billID > 200; billID-- {
+			fills = append(fills, testFill(billID, "BTC-USDT"))
+		}
+		writeOK(w, fills)
+	})
+	// Cursor của symbol có thể vượt quá cursor market khi batch của symbol khác lưu lỗi,
+	// fill phải được lấy lại từ cursor market
+	cursors := exchanges.SyncCursors{
+		"":         {LastTradeID: 250},
+		"ETH-USDT": {Symbol: "ETH-USDT", LastTradeID: 290},
+	}
+	orders, err := exchange.FetchTrades(context.Background(), primitive.NewObjectID(), cursors)
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+	if len(orders) != 50 {
+		t.Fatalf("got %d orders, want 50", len(orders))
+	}
+}
+
+func TestInvalidKeyErrorCodes(t *testing.T) {
+	tests := []struct {
+		code    string
+		invalid bool
+	}{
+		{code: "50111", invalid: true},
+		{code: "50113", invalid: true},
+		{code: "50105", invalid: true},
+		{code: "51000", invalid: false},
+	}
+	for _, tt := range tests {
+		exchange := newTestExchange(t, "spot", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": tt.code, "msg": "error", "data": []interface{}{}})
+		})
+		_, err := exchange.KeyPermissions(context.Background())
+		if err == nil {
+			t.Fatalf("code %s: expected error", tt.code)
+		}
+		if got := errors.Is(err, exchanges.ErrInvalidAPIKey); got != tt.invalid {
+			t.Errorf("code %s: errors.Is(ErrInvalidAPIKey) = %v, want %v", tt.code, got, tt.invalid)
+		}
+		var apiErr *APIError
+		if !tt.invalid && !errors.As(err, &apiErr) {
+			t.Errorf("code %s: expected *APIError, got %T", tt.code, err)
+		}
+	}
+}
+
+func TestFillToOrderFlipsFeeSign(t *testing.T) {
+	exchange := &OKXExchange{market: "spot", instType: "SPOT"}
+	paid := exchange.fillToOrder(primitive.NewObjectID(), testFill(1, "BTC-USDT"))
+	if !paid.Commission.Equal(models.DecimalFromString("0.2").Decimal) {
+		t.Errorf("commission = %s, want 0.2", paid.Commission)
+	}
+	if !paid.QuoteQuantity.Equal(models.DecimalFromString("200").Decimal) {
+		t.Errorf("quote quantity = %s, want 200", paid.QuoteQuantity)
+	}
+
+	rebate := testFill(2, "BTC-USDT")
+	rebate.Fee = "0.05"
+	if order := exchange.fillToOrder(primitive.NewObjectID(), rebate); !order.Commission.Equal(models.DecimalFromString("-0.05").Decimal) {
+		t.Errorf("rebate commission = %s, want -0.05", order.Commission)
+	}
+}
+
+func TestFetchTradesSwapQuoteQuantity(t *testing.T) {
+	exchange := newTestExchange(t, "swap", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/public/instruments":
+			writeOK(w, []okxInstrument{
+				{InstID: "BTC-USDT-SWAP", CtVal: "0.01", CtType: "linear"},
+				{InstID: "BTC-USD-SWAP", CtVal: "100", CtType: "inverse"},
+			})
+		case "/api/v5/trade/fills-history":
+			writeOK(w, []okxFill{testFill(2, "BTC-USDT-SWAP"), testFill(1, "BTC-USD-SWAP")})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	orders, err := exchange.FetchTrades(context.Background(), primitive.NewObjectID(), exchanges.SyncCursors{})
+	if err != nil {
+		t.Fatalf("FetchTrades: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("got %d orders, want 2", len(orders))
+	}
+	// linear: 100 x 2 x 0.01, inverse: 2 x 100 USD
+	if !orders[0].QuoteQuantity.Equal(models.DecimalFromString("2").Decimal) {
+		t.Errorf("linear quote quantity = %s, want 2", orders[0].QuoteQuantity)
+	}
+	if !orders[1].QuoteQuantity.Equal(models.DecimalFromString("200").Decimal) {
+		t.Errorf("inverse quote quantity = %s, want 200", orders[1].QuoteQuantity)
+	}
+}
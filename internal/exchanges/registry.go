@@ -0,0 +1,221 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnsupportedMarket là lỗi exchange/market chưa có adapter hoặc không chạy được trên testnet
+var ErrUnsupportedMarket = errors.New("unsupported exchange or market")
+
+// ErrIncomeNotSupported là lỗi exchange/market không có lịch sử income
+var ErrIncomeNotSupported = errors.New("income history is not supported")
+
+// ErrOrderHistoryNotSupported là lỗi exchange/market không lấy được lịch sử order
+var ErrOrderHistoryNotSupported = errors.New("order history is not supported")
+
+// ErrUserStreamNotSupported là lỗi exchange/market không có user data stream
+var ErrUserStreamNotSupported = errors.New("user data stream is not supported")
+
+// ErrTransfersNotSupported là lỗi exchange/market không lấy được lịch sử nạp/rút/chuyển tiền
+var ErrTransfersNotSupported = errors.New("transfer history is not supported")
+
+// ErrBalancesNotSupported là lỗi exchange/market không đọc được số dư
+var ErrBalancesNotSupported = errors.New("balance snapshot is not supported")
+
+// Credentials là API key chưa mã hóa dùng để tạo client
+type Credentials struct {
+	APIKey     string
+	Secret     string
+	Passphrase string
+}
+
+// Market mô tả một market của adapter
+type Market struct {
+	Name            string
+	SupportsTestnet bool
+	// NewIncomeFetcher tạo client lấy lịch sử income, nil nếu market không có income
+	NewIncomeFetcher func(creds Credentials, isTestnet bool) IncomeFetcher
+	// NewOrderHistoryFetcher tạo client lấy lịch sử order, nil nếu market chỉ lấy được fill
+	NewOrderHistoryFetcher func(creds Credentials, isTestnet bool) OrderHistoryFetcher
+	// NewUserStreamer tạo client user data stream, nil nếu market chỉ đồng bộ qua REST
+	NewUserStreamer func(creds Credentials, isTestnet bool) UserStreamer
+	// NewTransferFetcher tạo client lấy lịch sử nạp/rút/chuyển tiền, nil nếu market không hỗ trợ.
+	// Lịch sử ví chỉ có trên mainnet nên không được gọi với tài khoản testnet.
+	NewTransferFetcher func(creds Credentials) TransferFetcher
+	// NewBalanceFetcher tạo client đọc số dư, nil nếu market chưa hỗ trợ snapshot số dư
+	NewBalanceFetcher func(creds Credentials, isTestnet bool) BalanceFetcher
+}
+
+// Adapter mô tả một exchange: các market hỗ trợ, yêu cầu về credential và hàm tạo client
+type Adapter struct {
+	Exchange           string
+	Markets            []Market
+	RequiresPassphrase bool
+	// New tạo client lấy trade cho market, market luôn thuộc Markets
+	New func(market string, creds Credentials, isTestnet bool) (ExchangeFetcher, error)
+}
+
+// Market tìm market theo tên
+func (a Adapter) Market(name string) (Market, bool) {
+	for _, market := range a.Markets {
+		if market.Name == name {
+			return market, true
+		}
+	}
+	return Market{}, false
+}
+
+// Registry chứa các adapter đã đăng ký theo tên exchange
+type Registry struct {
+	adapters map[string]Adapter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{adapters: make(map[string]Adapter)}
+}
+
+// Register thêm adapter, lỗi nếu exchange đã được đăng ký hoặc adapter thiếu thông tin
+func (r *Registry) Register(adapter Adapter) error {
+	if adapter.Exchange == "" || adapter.New == nil || len(adapter.Markets) == 0 {
+		return fmt.Errorf("adapter %q must have an exchange name, markets and a constructor", adapter.Exchange)
+	}
+	if _, exists := r.adapters[adapter.Exchange]; exists {
+		return fmt.Errorf("exchange %q is already registered", adapter.Exchange)
+	}
+	r.adapters[adapter.Exchange] = adapter
+	return nil
+}
+
+// Adapter tìm adapter theo tên exchange
+func (r *Registry) Adapter(exchange string) (Adapter, bool) {
+	adapter, ok := r.adapters[exchange]
+	return adapter, ok
+}
+
+// Adapters trả về các adapter theo thứ tự tên exchange
+func (r *Registry) Adapters() []Adapter {
+	adapters := make([]Adapter, 0, len(r.adapters))
+	for _, adapter := range r.adapters {
+		adapters = append(adapters, adapter)
+	}
+	sort.Slice(adapters, func(i, j int) bool { return adapters[i].Exchange < adapters[j].Exchange })
+	return adapters
+}
+
+// Lookup tìm adapter và market, trả về ErrUnsupportedMarket nếu không có hoặc market không chạy trên testnet
+func (r *Registry) Lookup(exchange, market string, isTestnet bool) (Adapter, Market, error) {
+	adapter, ok := r.adapters[exchange]
+	if !ok {
+		return Adapter{}, Market{}, fmt.Errorf("%w: exchange %s", ErrUnsupportedMarket, exchange)
+	}
+	spec, ok := adapter.Market(market)
+	if !ok {
+		return Adapter{}, Market{}, fmt.Errorf("%w: market %s for exchange %s", ErrUnsupportedMarket, market, exchange)
+	}
+	if isTestnet && !spec.SupportsTestnet {
+		return Adapter{}, Market{}, fmt.Errorf("%w: market %s of %s is not available on testnet", ErrUnsupportedMarket, market, exchange)
+	}
+	return adapter, spec, nil
+}
+
+// NewFetcher tạo client lấy trade cho exchange/market
+func (r *Registry) NewFetcher(exchange, market string, creds Credentials, isTestnet bool) (ExchangeFetcher, error) {
+	adapter, _, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.New(market, creds, isTestnet)
+}
+
+// NewIncomeFetcher tạo client lấy income, trả về ErrIncomeNotSupported nếu market không có income
+func (r *Registry) NewIncomeFetcher(exchange, market string, creds Credentials, isTestnet bool) (IncomeFetcher, error) {
+	_, spec, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NewIncomeFetcher == nil {
+		return nil, ErrIncomeNotSupported
+	}
+	return spec.NewIncomeFetcher(creds, isTestnet), nil
+}
+
+// SupportsIncome cho biết exchange/market có lịch sử income hay không
+func (r *Registry) SupportsIncome(exchange, market string) bool {
+	_, spec, err := r.Lookup(exchange, market, false)
+	return err == nil && spec.NewIncomeFetcher != nil
+}
+
+// NewOrderHistoryFetcher tạo client lấy lịch sử order, trả về ErrOrderHistoryNotSupported nếu market không hỗ trợ
+func (r *Registry) NewOrderHistoryFetcher(exchange, market string, creds Credentials, isTestnet bool) (OrderHistoryFetcher, error) {
+	_, spec, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NewOrderHistoryFetcher == nil {
+		return nil, ErrOrderHistoryNotSupported
+	}
+	return spec.NewOrderHistoryFetcher(creds, isTestnet), nil
+}
+
+// SupportsOrderHistory cho biết exchange/market có lịch sử order hay không
+func (r *Registry) SupportsOrderHistory(exchange, market string) bool {
+	_, spec, err := r.Lookup(exchange, market, false)
+	return err == nil && spec.NewOrderHistoryFetcher != nil
+}
+
+// NewUserStreamer tạo client user data stream, trả về ErrUserStreamNotSupported nếu market không hỗ trợ
+func (r *Registry) NewUserStreamer(exchange, market string, creds Credentials, isTestnet bool) (UserStreamer, error) {
+	_, spec, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NewUserStreamer == nil {
+		return nil, ErrUserStreamNotSupported
+	}
+	return spec.NewUserStreamer(creds, isTestnet), nil
+}
+
+// SupportsUserStream cho biết exchange/market có user data stream hay không
+func (r *Registry) SupportsUserStream(exchange, market string) bool {
+	_, spec, err := r.Lookup(exchange, market, false)
+	return err == nil && spec.NewUserStreamer != nil
+}
+
+// NewTransferFetcher tạo client lấy lịch sử nạp/rút/chuyển tiền, trả về ErrTransfersNotSupported nếu
+// market không hỗ trợ hoặc tài khoản là testnet
+func (r *Registry) NewTransferFetcher(exchange, market string, creds Credentials, isTestnet bool) (TransferFetcher, error) {
+	_, spec, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NewTransferFetcher == nil || isTestnet {
+		return nil, ErrTransfersNotSupported
+	}
+	return spec.NewTransferFetcher(creds), nil
+}
+
+// SupportsTransfers cho biết exchange/market có lịch sử nạp/rút/chuyển tiền hay không
+func (r *Registry) SupportsTransfers(exchange, market string) bool {
+	_, spec, err := r.Lookup(exchange, market, false)
+	return err == nil && spec.NewTransferFetcher != nil
+}
+
+// NewBalanceFetcher tạo client đọc số dư, trả về ErrBalancesNotSupported nếu market không hỗ trợ
+func (r *Registry) NewBalanceFetcher(exchange, market string, creds Credentials, isTestnet bool) (BalanceFetcher, error) {
+	_, spec, err := r.Lookup(exchange, market, isTestnet)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NewBalanceFetcher == nil {
+		return nil, ErrBalancesNotSupported
+	}
+	return spec.NewBalanceFetcher(creds, isTestnet), nil
+}
+
+// SupportsBalances cho biết exchange/market có snapshot số dư hay không
+func (r *Registry) SupportsBalances(exchange, market string) bool {
+	_, spec, err := r.Lookup(exchange, market, false)
+	return err == nil && spec.NewBalanceFetcher != nil
+}
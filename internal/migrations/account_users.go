@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	register(Migration{
+		Name:        "0002_registered_account_users",
+		Description: "Tạo user theo username của các tài khoản cũ và gắn user_id cho tài khoản",
+		Run:         migrateRegisteredAccountUsers,
+	})
+}
+
+// Các user được tạo chưa có mật khẩu, chủ tài khoản đăng nhập qua /login/apikey
+func migrateRegisteredAccountUsers(ctx context.Context, db *mongo.Database) error {
+	accounts := db.Collection("registered_accounts")
+	users := db.Collection("users")
+	usernames, err := accounts.Distinct(ctx, "username", bson.M{"user_id": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	for _, value := range usernames {
+		username, ok := value.(string)
+		if !ok || username == "" {
+			continue
+		}
+		// Upsert theo username để chạy lại không tạo user trùng
+		var user struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err := users.FindOneAndUpdate(ctx,
+			bson.M{"username": username},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":        primitive.NewObjectID(),
+				"username":   username,
+				"created_at": time.Now(),
+			}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&user)
+		if err != nil {
+			return err
+		}
+		result, err := accounts.UpdateMany(ctx,
+			bson.M{"username": username, "user_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"user_id": user.ID}},
+		)
+		if err != nil {
+			return err
+		}
+		logrus.WithFields(logrus.Fields{
+			"username": username,
+			"user_id":  user.ID.Hex(),
+			"modified": result.ModifiedCount,
+		}).Info("Linked registered accounts to user")
+	}
+	return nil
+}
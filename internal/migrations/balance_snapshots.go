@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const balanceSnapshotsCollection = "balance_snapshots"
+
+func init() {
+	register(Migration{
+		Name:        "0004_balance_snapshots_timeseries",
+		Description: "Tạo time-series collection balance_snapshots cho snapshot số dư của tài khoản",
+		Run:         migrateBalanceSnapshots,
+	})
+}
+
+// Cần MongoDB 5.0 trở lên. Collection đã tồn tại thì bỏ qua bước tạo, chỉ tạo index.
+func migrateBalanceSnapshots(ctx context.Context, db *mongo.Database) error {
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": balanceSnapshotsCollection})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		timeSeries := options.TimeSeries().
+			SetTimeField("time").
+			SetMetaField("registered_account_id").
+			SetGranularity("hours")
+		if err := db.CreateCollection(ctx, balanceSnapshotsCollection, options.CreateCollection().SetTimeSeriesOptions(timeSeries)); err != nil {
+			return err
+		}
+		logrus.WithField("collection", balanceSnapshotsCollection).Info("Created balance snapshots time-series collection")
+	}
+	name, err := db.Collection(balanceSnapshotsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "registered_account_id", Value: 1},
+			{Key: "time", Value: -1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	logrus.WithField("index", name).Info("Created balance snapshot index")
+	return nil
+}
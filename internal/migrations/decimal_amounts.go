@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Các trường số tiền của orders trước đây lưu dạng chuỗi
+var orderAmountFields = []string{"price", "quantity", "quote_quantity", "executed_quantity", "avg_price", "commission"}
+
+func init() {
+	register(Migration{
+		Name:        "0001_orders_decimal_amounts",
+		Description: "Chuyển các trường số tiền của orders từ chuỗi sang Decimal128",
+		Run:         migrateOrderAmountsToDecimal,
+	})
+}
+
+func migrateOrderAmountsToDecimal(ctx context.Context, db *mongo.Database) error {
+	orders := db.Collection("orders")
+	zero, _ := primitive.ParseDecimal128("0")
+	for _, field := range orderAmountFields {
+		// Chuyển đổi ngay trên server bằng update pipeline, chuỗi rỗng/không hợp lệ thành 0
+		filter := bson.M{field: bson.M{"$type": "string"}}
+		update := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				field: bson.M{"$convert": bson.M{
+					"input":   "$" + field,
+					"to":      "decimal",
+					"onError": zero,
+					"onNull":  zero,
+				}},
+			}}},
+		}
+		result, err := orders.UpdateMany(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		logrus.WithFields(logrus.Fields{
+			"field":    field,
+			"modified": result.ModifiedCount,
+		}).Info("Converted order amounts to Decimal128")
+	}
+	return nil
+}
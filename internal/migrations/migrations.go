@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migration là một bước chuyển đổi dữ liệu chạy một lần, phải chạy lại được an toàn
+type Migration struct {
+	Name        string
+	Description string
+	Run         func(ctx context.Context, db *mongo.Database) error
+}
+
+var registry = map[string]Migration{}
+
+func register(m Migration) {
+	registry[m.Name] = m
+}
+
+// Get trả về migration theo tên
+func Get(name string) (Migration, error) {
+	m, ok := registry[name]
+	if !ok {
+		return Migration{}, fmt.Errorf("unknown migration: %s", name)
+	}
+	return m, nil
+}
+
+// All trả về toàn bộ migration theo thứ tự tên
+func All() []Migration {
+	all := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
@@ -0,0 +1,223 @@
+package migrations
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Index unique cũ theo order, gộp mất các fill của cùng một order
+const legacyOrderIndex = "order_id_1_exchange_1_market_1"
+
+func init() {
+	register(Migration{
+		Name:        "0003_orders_fill_identity",
+		Description: "Đổi khóa unique của orders từ order_id sang trade ID của từng fill, xóa fill trùng và đặt lại cursor trade để đồng bộ lại",
+		Run:         migrateOrderFillIdentity,
+	})
+}
+
+// Fill bị gộp trước đây không khôi phục được từ dữ liệu đã lưu nên cursor trade của các
+// account/exchange/market có order được đặt về 0 để lần đồng bộ sau lấy lại toàn bộ fill. Cursor chỉ bị
+// xóa khi index cũ còn tồn tại, chạy lại migration sau khi đã xong không đồng bộ lại từ đầu lần nữa.
+func migrateOrderFillIdentity(ctx context.Context, db *mongo.Database) error {
+	orders := db.Collection("orders")
+	legacy, err := indexExists(ctx, orders, legacyOrderIndex)
+	if err != nil {
+		return err
+	}
+	if legacy {
+		if err := resetTradeCursors(ctx, orders, db.Collection("sync_state")); err != nil {
+			return err
+		}
+	}
+	if err := dropIndexIfExists(ctx, orders, legacyOrderIndex); err != nil {
+		return err
+	}
+	if err := removeDuplicateFills(ctx, orders); err != nil {
+		return err
+	}
+	names, err := orders.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "registered_account_id", Value: 1},
+				{Key: "exchange", Value: 1},
+				{Key: "market", Value: 1},
+				{Key: "symbol", Value: 1},
+				{Key: "id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "registered_account_id", Value: 1},
+				{Key: "exchange", Value: 1},
+				{Key: "market", Value: 1},
+				{Key: "symbol", Value: 1},
+				{Key: "order_id", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	logrus.WithField("indexes", names).Info("Created order fill indexes")
+	return nil
+}
+
+func indexExists(ctx context.Context, collection *mongo.Collection, name string) (bool, error) {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, spec := range specs {
+		if spec.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resetTradeCursors xóa cursor theo symbol và mốc synced_until của market trade (không đụng tới
+// mốc income, lịch sử order hay nạp/rút) cho mọi account/exchange/market đã có order, rồi ghi lại
+// cursor bằng 0 cho từng symbol đã có fill. Không để trống sync_state vì khi đó cursor được dựng lại
+// từ trade ID lớn nhất đã lưu và fill bị gộp sẽ không được lấy lại; cursor 0 cũng giữ cho symbol
+// spot/margin đã hết số dư vẫn được đồng bộ lại.
+func resetTradeCursors(ctx context.Context, orders, syncStates *mongo.Collection) error {
+	cursor, err := orders.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"registered_account_id": "$registered_account_id",
+				"exchange":              "$exchange",
+				"market":                "$market",
+			},
+			// Lãi margin không phải trade nên không có cursor
+			"symbols": bson.M{"$addToSet": bson.M{"$cond": bson.A{
+				bson.M{"$ne": bson.A{"$type", models.OrderTypeMarginInterest}}, "$symbol", nil,
+			}}},
+		}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	var accounts, removed, reset int64
+	now := time.Now()
+	for cursor.Next(ctx) {
+		var group struct {
+			Key struct {
+				RegisteredAccountID primitive.ObjectID `bson:"registered_account_id"`
+				Exchange            string             `bson:"exchange"`
+				Market              string             `bson:"market"`
+			} `bson:"_id"`
+			Symbols []*string `bson:"symbols"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+		result, err := syncStates.DeleteMany(ctx, bson.M{
+			"registered_account_id": group.Key.RegisteredAccountID,
+			"exchange":              group.Key.Exchange,
+			"market":                group.Key.Market,
+		})
+		if err != nil {
+			return err
+		}
+		accounts++
+		removed += result.DeletedCount
+		var states []interface{}
+		for _, symbol := range group.Symbols {
+			if symbol == nil || *symbol == "" {
+				continue
+			}
+			states = append(states, models.SyncState{
+				RegisteredAccountID: group.Key.RegisteredAccountID,
+				Exchange:            group.Key.Exchange,
+				Market:              group.Key.Market,
+				Symbol:              *symbol,
+				UpdatedAt:           now,
+			})
+		}
+		if len(states) == 0 {
+			continue
+		}
+		if _, err := syncStates.InsertMany(ctx, states); err != nil {
+			return err
+		}
+		reset += int64(len(states))
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"accounts": accounts,
+		"removed":  removed,
+		"reset":    reset,
+	}).Warn("Reset trade sync cursors, affected accounts will be re-synced from scratch on the next trade history run")
+	return nil
+}
+
+func dropIndexIfExists(ctx context.Context, collection *mongo.Collection, name string) error {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Name == name {
+			if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+				return err
+			}
+			logrus.WithField("index", name).Info("Dropped legacy order index")
+			return nil
+		}
+	}
+	return nil
+}
+
+// removeDuplicateFills giữ lại một document cho mỗi khóa fill, xóa các bản còn lại
+func removeDuplicateFills(ctx context.Context, orders *mongo.Collection) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"registered_account_id": "$registered_account_id",
+				"exchange":              "$exchange",
+				"market":                "$market",
+				"symbol":                "$symbol",
+				"id":                    "$id",
+			},
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+	cursor, err := orders.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	var removed int64
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs []interface{} `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+		result, err := orders.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": group.IDs[1:]}})
+		if err != nil {
+			return err
+		}
+		removed += result.DeletedCount
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	logrus.WithField("removed", removed).Info("Removed duplicate order fills")
+	return nil
+}
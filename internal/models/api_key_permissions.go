@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// APIKeyPermissions là quyền của API key do exchange trả về khi kiểm tra lúc đăng ký
+type APIKeyPermissions struct {
+	Reading           bool      `bson:"reading" json:"reading"`
+	SpotTrading       bool      `bson:"spot_trading" json:"spotTrading"`
+	MarginTrading     bool      `bson:"margin_trading" json:"marginTrading"`
+	FuturesTrading    bool      `bson:"futures_trading" json:"futuresTrading"`
+	OptionsTrading    bool      `bson:"options_trading" json:"optionsTrading"`
+	Withdrawals       bool      `bson:"withdrawals" json:"withdrawals"`
+	InternalTransfer  bool      `bson:"internal_transfer" json:"internalTransfer"`
+	UniversalTransfer bool      `bson:"universal_transfer" json:"universalTransfer"`
+	IPRestricted      bool      `bson:"ip_restricted" json:"ipRestricted"`
+	CheckedAt         time.Time `bson:"checked_at" json:"checkedAt"`
+}
+
+// CanMoveFunds cho biết key có quyền giao dịch, rút tiền hoặc chuyển tiền (nội bộ, universal transfer
+// của Binance, chuyển tiền sub account của Bybit)
+func (p APIKeyPermissions) CanMoveFunds() bool {
+	return p.SpotTrading || p.MarginTrading || p.FuturesTrading || p.OptionsTrading ||
+		p.Withdrawals || p.InternalTransfer || p.UniversalTransfer
+}
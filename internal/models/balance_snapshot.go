@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssetBalance là số dư của một asset tại thời điểm chụp snapshot
+type AssetBalance struct {
+	Asset            string  `bson:"asset" json:"asset"`
+	Free             Decimal `bson:"free" json:"free"`                          // Spot: số dư khả dụng; futures: availableBalance
+	Locked           Decimal `bson:"locked" json:"locked"`                      // Spot: số dư bị giữ bởi order đang mở
+	WalletBalance    Decimal `bson:"wallet_balance" json:"walletBalance"`       // Futures: số dư ví, chưa gồm PnL chưa chốt
+	UnrealizedProfit Decimal `bson:"unrealized_profit" json:"unrealizedProfit"` // Futures: PnL chưa chốt của vị thế đang mở
+	MarginBalance    Decimal `bson:"margin_balance" json:"marginBalance"`       // Futures: số dư ví cộng PnL chưa chốt
+	Equity           Decimal `bson:"equity" json:"equity"`                      // Giá trị tính equity: spot free+locked, futures marginBalance
+}
+
+// BalanceSnapshot là số dư của một tài khoản tại một thời điểm, lưu trong time-series collection
+// balance_snapshots với metaField registered_account_id
+type BalanceSnapshot struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	Time                time.Time          `bson:"time" json:"time"`
+	Balances            []AssetBalance     `bson:"balances" json:"balances"`
+}
+
+// DailyEquity là equity của tài khoản theo snapshot cuối cùng trong ngày (UTC)
+type DailyEquity struct {
+	Date         time.Time `json:"date"`
+	SnapshotTime time.Time `json:"snapshotTime"`
+	Equity       string    `json:"equity"`
+	Unconverted  []string  `json:"unconverted,omitempty"` // Asset không quy đổi được, không được cộng vào equity
+}
+
+// EquityCurve là equity theo ngày của một account trong kỳ, quy đổi sang Asset
+type EquityCurve struct {
+	RegisteredAccountID primitive.ObjectID `json:"registeredAccountID"`
+	Asset               string             `json:"asset"`
+	PeriodStart         time.Time          `json:"periodStart"`
+	PeriodEnd           time.Time          `json:"periodEnd"`
+	Days                []DailyEquity      `json:"days"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Decimal là số thập phân chính xác, lưu trong Mongo dưới dạng Decimal128
+// và trả về JSON dưới dạng chuỗi
+type Decimal struct {
+	decimal.Decimal
+}
+
+func NewDecimal(d decimal.Decimal) Decimal {
+	return Decimal{Decimal: d}
+}
+
+// DecimalFromString parse chuỗi số từ exchange, chuỗi rỗng hoặc không hợp lệ trả về 0
+func DecimalFromString(s string) Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Decimal{}
+	}
+	return Decimal{Decimal: d}
+}
+
+func (d Decimal) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	d128, err := primitive.ParseDecimal128(d.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(d128)
+}
+
+// UnmarshalBSONValue đọc Decimal128, ngoài ra vẫn đọc được dữ liệu cũ lưu dạng chuỗi hoặc số
+func (d *Decimal) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Decimal128:
+		parsed, err := decimal.NewFromString(value.Decimal128().String())
+		if err != nil {
+			return err
+		}
+		d.Decimal = parsed
+	case bsontype.String:
+		*d = DecimalFromString(value.StringValue())
+	case bsontype.Double:
+		d.Decimal = decimal.NewFromFloat(value.Double())
+	case bsontype.Int32:
+		d.Decimal = decimal.NewFromInt32(value.Int32())
+	case bsontype.Int64:
+		d.Decimal = decimal.NewFromInt(value.Int64())
+	case bsontype.Null, bsontype.Undefined:
+		d.Decimal = decimal.Zero
+	default:
+		return fmt.Errorf("cannot decode %s into Decimal", t)
+	}
+	return nil
+}
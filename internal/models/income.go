@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Các loại income futures hay dùng trong báo cáo, Binance còn trả về nhiều loại khác
+// (INSURANCE_CLEAR, WELCOME_BONUS, ...) và vẫn được lưu nguyên
+const (
+	IncomeTypeRealizedPnL      = "REALIZED_PNL"
+	IncomeTypeFundingFee       = "FUNDING_FEE"
+	IncomeTypeCommission       = "COMMISSION"
+	IncomeTypeCommissionRebate = "COMMISSION_REBATE"
+	IncomeTypeTransfer         = "TRANSFER"
+)
+
+// Income là một bản ghi biến động số dư futures (PnL đã chốt, funding, phí, hoàn phí, ...).
+// Income âm là tiền bị trừ khỏi tài khoản.
+type Income struct {
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	Symbol              string             `bson:"symbol" json:"symbol"`
+	IncomeType          string             `bson:"income_type" json:"incomeType"`
+	Income              Decimal            `bson:"income" json:"income"`
+	Asset               string             `bson:"asset" json:"asset"`
+	Info                string             `bson:"info" json:"info"`
+	TranID              int64              `bson:"tran_id" json:"tranID"`
+	TradeID             string             `bson:"trade_id" json:"tradeID"`
+	Time                time.Time          `bson:"time" json:"time"`
+}
+
+// IncomeTotal là tổng income của một loại và một asset trong kỳ
+type IncomeTotal struct {
+	IncomeType string `bson:"income_type" json:"incomeType"`
+	Asset      string `bson:"asset" json:"asset"`
+	Amount     string `bson:"amount" json:"amount"`
+	Count      int    `bson:"count" json:"count"`
+}
+
+// IncomeSummary là tổng hợp income của một account trong kỳ theo loại và asset
+type IncomeSummary struct {
+	RegisteredAccountID primitive.ObjectID `json:"registeredAccountID"`
+	PeriodStart         time.Time          `json:"periodStart"`
+	PeriodEnd           time.Time          `json:"periodEnd"`
+	Totals              []IncomeTotal      `json:"totals"`
+}
@@ -6,6 +6,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// OrderTypeMarginInterest đánh dấu bản ghi lãi vay margin lưu chung collection orders,
+// không phải trade nên không tính vào phí giao dịch và khối lượng hoàn phí
+const OrderTypeMarginInterest = "MARGIN_INTEREST"
+
 type Order struct {
 	ID                  string             `bson:"id"`
 	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id"`
@@ -16,14 +20,44 @@ type Order struct {
 	Side                string             `bson:"side"`
 	PositionSide        string             `bson:"position_side"`
 	Type                string             `bson:"type"`
-	Price               string             `bson:"price"`
-	Quantity            string             `bson:"quantity"`
-	ExecutedQuantity    string             `bson:"executed_quantity"`
-	AvgPrice            string             `bson:"avg_price"`
+	Price               Decimal            `bson:"price"`
+	Quantity            Decimal            `bson:"quantity"`
+	ExecutedQuantity    Decimal            `bson:"executed_quantity"`
+	AvgPrice            Decimal            `bson:"avg_price"`
 	Time                time.Time          `bson:"time"`
-	Commission          string             `bson:"commission"`
+	Commission          Decimal            `bson:"commission"`
 	CommissionAsset     string             `bson:"commission_asset"`
 	OrderID             int64              `bson:"order_id"`
 	OrderListId         int64              `bson:"order_list_id"`
-	QuoteQuantity       string             `bson:"quote_quantity,omitempty"`
+	QuoteQuantity       Decimal            `bson:"quote_quantity"`
+}
+
+// IsTrade trả về false với các bản ghi không phải trade (lãi vay margin)
+func (o Order) IsTrade() bool {
+	return o.Type != OrderTypeMarginInterest
+}
+
+// OrderCommission là tổng phí của một order theo commission asset
+type OrderCommission struct {
+	Asset  string  `bson:"asset" json:"asset"`
+	Amount Decimal `bson:"amount" json:"amount"`
+}
+
+// AggregatedOrder là order gộp từ các fill đã lưu: khối lượng và phí là tổng của các fill,
+// AvgPrice là giá khớp trung bình theo khối lượng
+type AggregatedOrder struct {
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	Symbol              string             `bson:"symbol" json:"symbol"`
+	OrderID             int64              `bson:"order_id" json:"orderID"`
+	Side                string             `bson:"side" json:"side"`
+	PositionSide        string             `bson:"position_side" json:"positionSide"`
+	ExecutedQuantity    Decimal            `bson:"executed_quantity" json:"executedQuantity"`
+	QuoteQuantity       Decimal            `bson:"quote_quantity" json:"quoteQuantity"`
+	AvgPrice            Decimal            `bson:"avg_price" json:"avgPrice"`
+	Commissions         []OrderCommission  `bson:"commissions" json:"commissions"`
+	FillCount           int                `bson:"fill_count" json:"fillCount"`
+	FirstFillTime       time.Time          `bson:"first_fill_time" json:"firstFillTime"`
+	LastFillTime        time.Time          `bson:"last_fill_time" json:"lastFillTime"`
 }
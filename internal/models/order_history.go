@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Trạng thái cuối của order, order ở các trạng thái khác (NEW, PARTIALLY_FILLED, ...) còn có thể thay đổi
+var finalOrderStatuses = map[string]struct{}{
+	"FILLED":           {},
+	"CANCELED":         {},
+	"EXPIRED":          {},
+	"REJECTED":         {},
+	"EXPIRED_IN_MATCH": {},
+}
+
+// OrderHistory là vòng đời của một order (kể cả order bị hủy/hết hạn không có fill),
+// liên kết với các fill trong collection orders qua account/exchange/market/symbol/order_id
+type OrderHistory struct {
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	Symbol              string             `bson:"symbol" json:"symbol"`
+	OrderID             int64              `bson:"order_id" json:"orderID"`
+	ClientOrderID       string             `bson:"client_order_id" json:"clientOrderID"`
+	OrderListId         int64              `bson:"order_list_id" json:"orderListID"`
+	Status              string             `bson:"status" json:"status"`
+	Type                string             `bson:"type" json:"type"`
+	Side                string             `bson:"side" json:"side"`
+	PositionSide        string             `bson:"position_side" json:"positionSide"`
+	TimeInForce         string             `bson:"time_in_force" json:"timeInForce"`
+	Price               Decimal            `bson:"price" json:"price"`
+	StopPrice           Decimal            `bson:"stop_price" json:"stopPrice"`
+	Quantity            Decimal            `bson:"quantity" json:"quantity"`
+	ExecutedQuantity    Decimal            `bson:"executed_quantity" json:"executedQuantity"`
+	QuoteQuantity       Decimal            `bson:"quote_quantity" json:"quoteQuantity"`
+	AvgPrice            Decimal            `bson:"avg_price" json:"avgPrice"`
+	ReduceOnly          bool               `bson:"reduce_only" json:"reduceOnly"`
+	Time                time.Time          `bson:"time" json:"time"`
+	UpdateTime          time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// IsFinal trả về true khi order đã khớp hết, bị hủy, hết hạn hoặc bị từ chối
+func (o OrderHistory) IsFinal() bool {
+	_, ok := finalOrderStatuses[o.Status]
+	return ok
+}
+
+// OrderHistoryWithFills là order kèm các fill đã lưu của order
+type OrderHistoryWithFills struct {
+	OrderHistory `bson:",inline"`
+	Fills        []Order `bson:"fills" json:"fills"`
+}
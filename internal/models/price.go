@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Price là giá đóng cửa nến 1 phút của Asset tính theo Quote, dùng làm cache quy đổi phí
+type Price struct {
+	Asset  string    `bson:"asset"`
+	Quote  string    `bson:"quote"`
+	Time   time.Time `bson:"time"`
+	Price  string    `bson:"price"`
+	Source string    `bson:"source"` // Các symbol đã dùng để tính giá, ví dụ "BNBUSDT" hoặc "XBTC*BTCUSDT"
+}
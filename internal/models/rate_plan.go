@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RateTier là một bậc hoàn phí theo khối lượng giao dịch trong kỳ (tính bằng asset thanh toán)
+type RateTier struct {
+	Name      string            `bson:"name" json:"name"`
+	MinVolume string            `bson:"min_volume" json:"minVolume"`
+	Rates     map[string]string `bson:"rates" json:"rates"` // Key: market, value: tỉ lệ hoàn phí (0.2 = 20%)
+}
+
+// RatePlan là gói hoàn phí, có thể gắn với một exchange và một partner giới thiệu
+type RatePlan struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Name       string             `bson:"name" json:"name"`
+	Exchange   string             `bson:"exchange,omitempty" json:"exchange,omitempty"`      // Rỗng: áp dụng cho mọi exchange
+	ReferrerID string             `bson:"referrer_id,omitempty" json:"referrerID,omitempty"` // Gói mặc định cho user do partner này giới thiệu
+	Tiers      []RateTier         `bson:"tiers" json:"tiers"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updatedAt"`
+}
+
+// RatePlanAssignment gán gói hoàn phí cho account từ thời điểm EffectiveFrom
+type RatePlanAssignment struct {
+	RatePlanID    primitive.ObjectID `bson:"rate_plan_id" json:"ratePlanID"`
+	EffectiveFrom time.Time          `bson:"effective_from" json:"effectiveFrom"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommissionTotal là tổng phí của một asset trong kỳ và giá trị quy đổi sang asset thanh toán
+type CommissionTotal struct {
+	Asset            string `bson:"asset" json:"asset"`
+	Amount           string `bson:"amount" json:"amount"`
+	SettlementAmount string `bson:"settlement_amount" json:"settlementAmount"`
+	TradeCount       int    `bson:"trade_count" json:"tradeCount"`
+	Unconverted      int    `bson:"unconverted" json:"unconverted"` // Số trade không quy đổi được
+}
+
+// RateBreakdown là phần hoàn phí theo từng gói/bậc được áp dụng trong kỳ
+type RateBreakdown struct {
+	RatePlanID      primitive.ObjectID `bson:"rate_plan_id,omitempty" json:"ratePlanID,omitempty"`
+	RatePlanName    string             `bson:"rate_plan_name,omitempty" json:"ratePlanName,omitempty"`
+	Tier            string             `bson:"tier,omitempty" json:"tier,omitempty"`
+	Rate            string             `bson:"rate" json:"rate"`
+	TradeCount      int                `bson:"trade_count" json:"tradeCount"`
+	TotalCommission string             `bson:"total_commission" json:"totalCommission"`
+	RebateAmount    string             `bson:"rebate_amount" json:"rebateAmount"`
+}
+
+// RebateStatement là bảng tính hoàn phí của một account trong một kỳ
+type RebateStatement struct {
+	ID                  primitive.ObjectID `bson:"_id" json:"id"`
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	PeriodStart         time.Time          `bson:"period_start" json:"periodStart"`
+	PeriodEnd           time.Time          `bson:"period_end" json:"periodEnd"`
+	SettlementAsset     string             `bson:"settlement_asset" json:"settlementAsset"`
+	RebateRate          string             `bson:"rebate_rate" json:"rebateRate"` // Tỉ lệ hoàn phí bình quân của kỳ
+	Volume              string             `bson:"volume" json:"volume"`          // Khối lượng giao dịch quy đổi, dùng để chọn bậc
+	Commissions         []CommissionTotal  `bson:"commissions" json:"commissions"`
+	Rates               []RateBreakdown    `bson:"rates" json:"rates"`
+	TotalCommission     string             `bson:"total_commission" json:"totalCommission"`
+	RebateAmount        string             `bson:"rebate_amount" json:"rebateAmount"`
+	TradeCount          int                `bson:"trade_count" json:"tradeCount"`
+	CreatedAt           time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+// FeeSummary là tổng phí giao dịch của một account trong kỳ, quy đổi sang asset thanh toán
+type FeeSummary struct {
+	RegisteredAccountID primitive.ObjectID `json:"registeredAccountID"`
+	PeriodStart         time.Time          `json:"periodStart"`
+	PeriodEnd           time.Time          `json:"periodEnd"`
+	SettlementAsset     string             `json:"settlementAsset"`
+	Commissions         []CommissionTotal  `json:"commissions"`
+	TotalCommission     string             `json:"totalCommission"`
+	TradeCount          int                `json:"tradeCount"`
+}
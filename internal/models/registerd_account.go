@@ -2,14 +2,43 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// Trạng thái đồng bộ của tài khoản, rỗng được coi là active
+const (
+	AccountStatusActive   = "active"
+	AccountStatusPaused   = "paused"   // User tạm dừng đồng bộ
+	AccountStatusDisabled = "disabled" // Operator tắt, ví dụ key lỗi
+)
+
 type RegisteredAccount struct {
-	ID                  primitive.ObjectID `bson:"_id"`
-	Username            string             `bson:"username"`
-	Exchange            string             `bson:"exchange"`
-	Market              string             `bson:"market"`
-	EncryptedAPIKey     string             `bson:"encrypted_api_key"`
-	EncryptedSecret     string             `bson:"encrypted_secret"`
-	EncryptedPassphrase string             `bson:"encrypted_passphrase,omitempty"` // Thêm cho OKX
-	ListenKey           string             `bson:"listen_key,omitempty"`
-	IsTestnet           bool               `bson:"is_testnet"`
+	ID                  primitive.ObjectID   `bson:"_id"`
+	UserID              primitive.ObjectID   `bson:"user_id,omitempty"` // User sở hữu tài khoản
+	Username            string               `bson:"username"`
+	Exchange            string               `bson:"exchange"`
+	Market              string               `bson:"market"`
+	EncryptedAPIKey     string               `bson:"encrypted_api_key"`
+	EncryptedSecret     string               `bson:"encrypted_secret"`
+	EncryptedPassphrase string               `bson:"encrypted_passphrase,omitempty"` // Thêm cho OKX
+	SecretBackend       string               `bson:"secret_backend,omitempty"`       // Nơi lưu API key, rỗng là mã hóa ngay trong document
+	WrappedDataKey      string               `bson:"wrapped_data_key,omitempty"`     // Data key của envelope encryption, mã hóa bằng master key
+	SecretRef           string               `bson:"secret_ref,omitempty"`           // Đường dẫn secret ở backend ngoài (Vault)
+	CredentialsVersion  int64                `bson:"credentials_version,omitempty"`  // Tăng mỗi lần user thay key, mã hóa lại key cũ không đổi
+	ListenKey           string               `bson:"listen_key,omitempty"`           // Listen key của user data stream đang mở
+	IsTestnet           bool                 `bson:"is_testnet"`
+	Status              string               `bson:"status,omitempty"`
+	ReferrerID          string               `bson:"referrer_id,omitempty"`
+	RatePlans           []RatePlanAssignment `bson:"rate_plans,omitempty"`  // Sắp xếp theo effective_from tăng dần
+	Permissions         *APIKeyPermissions   `bson:"permissions,omitempty"` // Quyền của API key lúc đăng ký
+}
+
+// EffectiveStatus trả về trạng thái của tài khoản, mặc định active
+func (a RegisteredAccount) EffectiveStatus() string {
+	if a.Status == "" {
+		return AccountStatusActive
+	}
+	return a.Status
+}
+
+// IsSyncEnabled cho biết tài khoản có được đồng bộ định kỳ hay không
+func (a RegisteredAccount) IsSyncEnabled() bool {
+	return a.EffectiveStatus() == AccountStatusActive
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SyncState lưu vị trí đồng bộ của một account theo exchange/market/symbol.
+// Bản ghi có Symbol rỗng đại diện cho cả market (lần chạy thành công/thất bại gần nhất).
+type SyncState struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Market              string             `bson:"market" json:"market"`
+	Symbol              string             `bson:"symbol" json:"symbol"`
+	LastTradeID         int64              `bson:"last_trade_id" json:"lastTradeID"`
+	LastTradeTime       time.Time          `bson:"last_trade_time" json:"lastTradeTime"`
+	SyncedUntil         time.Time          `bson:"synced_until,omitempty" json:"syncedUntil,omitempty"` // Mốc thời gian đã đồng bộ xong
+	LastSuccessAt       time.Time          `bson:"last_success_at,omitempty" json:"lastSuccessAt,omitempty"`
+	LastFailureAt       time.Time          `bson:"last_failure_at,omitempty" json:"lastFailureAt,omitempty"`
+	LastError           string             `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	UpdatedAt           time.Time          `bson:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Các loại biến động tiền ngoài giao dịch
+const (
+	TransferTypeDeposit    = "DEPOSIT"
+	TransferTypeWithdrawal = "WITHDRAWAL"
+	TransferTypeTransfer   = "TRANSFER" // Chuyển tiền nội bộ giữa các ví của cùng tài khoản
+)
+
+// Transfer là một lần nạp, rút hoặc chuyển tiền nội bộ của tài khoản. Status giữ nguyên giá trị
+// của exchange (Binance: 0 pending, 1 success, ... với deposit; CONFIRMED, ... với universal transfer)
+// và được cập nhật khi đồng bộ lại.
+type Transfer struct {
+	RegisteredAccountID primitive.ObjectID `bson:"registered_account_id" json:"registeredAccountID"`
+	Exchange            string             `bson:"exchange" json:"exchange"`
+	Type                string             `bson:"type" json:"type"`
+	TransferID          string             `bson:"transfer_id" json:"transferID"`
+	Asset               string             `bson:"asset" json:"asset"`
+	Amount              Decimal            `bson:"amount" json:"amount"`
+	Fee                 Decimal            `bson:"fee" json:"fee"`
+	Network             string             `bson:"network,omitempty" json:"network,omitempty"`
+	Address             string             `bson:"address,omitempty" json:"address,omitempty"`
+	TxID                string             `bson:"tx_id,omitempty" json:"txID,omitempty"`
+	Status              string             `bson:"status" json:"status"`
+	FromAccount         string             `bson:"from_account,omitempty" json:"fromAccount,omitempty"` // Ví nguồn của chuyển tiền nội bộ (MAIN, UMFUTURE, ...)
+	ToAccount           string             `bson:"to_account,omitempty" json:"toAccount,omitempty"`     // Ví đích của chuyển tiền nội bộ
+	Time                time.Time          `bson:"time" json:"time"`
+}
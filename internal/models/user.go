@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Vai trò của user, được đưa vào JWT claims
+const (
+	RoleUser     = "user"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// IsValidRole kiểm tra role có thuộc các vai trò đã định nghĩa
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleOperator, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// User là người dùng của hệ thống, sở hữu nhiều RegisteredAccount
+type User struct {
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	Username         string             `bson:"username" json:"username"`
+	PasswordHash     string             `bson:"password_hash,omitempty" json:"-"`
+	Role             string             `bson:"role,omitempty" json:"role"`                                    // Rỗng được coi là RoleUser
+	ExternalProvider string             `bson:"external_provider,omitempty" json:"externalProvider,omitempty"` // Đăng nhập qua danh tính bên ngoài
+	ExternalID       string             `bson:"external_id,omitempty" json:"externalID,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+// EffectiveRole trả về role của user, mặc định RoleUser
+func (u User) EffectiveRole() string {
+	if u.Role == "" {
+		return RoleUser
+	}
+	return u.Role
+}
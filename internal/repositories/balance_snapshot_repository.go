@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BalanceSnapshotRepository lưu snapshot số dư trong time-series collection (tạo bởi migration
+// 0004_balance_snapshots_timeseries), snapshot chỉ được thêm mới, không cập nhật
+type BalanceSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBalanceSnapshotRepository(client *mongo.Client, dbName, collectionName string) *BalanceSnapshotRepository {
+	return &BalanceSnapshotRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+func (r *BalanceSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot models.BalanceSnapshot) error {
+	_, err := r.collection.InsertOne(ctx, snapshot)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"registered_account_id": snapshot.RegisteredAccountID.Hex(),
+			"error":                 err,
+		}).Error("Failed to save balance snapshot")
+	}
+	return err
+}
+
+// GetSnapshotsInRange lấy snapshot của account trong khoảng [start, end) theo thứ tự thời gian
+func (r *BalanceSnapshotRepository) GetSnapshotsInRange(ctx context.Context, accountID primitive.ObjectID, start, end time.Time) ([]models.BalanceSnapshot, error) {
+	filter := bson.M{
+		"registered_account_id": accountID,
+		"time":                  bson.M{"$gte": start, "$lt": end},
+	}
+	var snapshots []models.BalanceSnapshot
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"time": 1}))
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to find balance snapshots in range")
+		return nil, err
+	}
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode balance snapshots")
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshotsByAccountID xóa toàn bộ snapshot của một tài khoản, trả về số bản ghi đã xóa.
+// Filter chỉ dùng metaField nên chạy được trên time-series collection.
+func (r *BalanceSnapshotRepository) DeleteSnapshotsByAccountID(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IncomeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewIncomeRepository(client *mongo.Client, dbName, collectionName string) *IncomeRepository {
+	return &IncomeRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveIncomes upsert income theo account/exchange/market và tranId của exchange. Một giao dịch
+// có thể sinh nhiều income cùng tranId (PnL và phí của cùng trade) nên khóa gồm cả loại và asset.
+func (r *IncomeRepository) SaveIncomes(ctx context.Context, incomes []models.Income) error {
+	if len(incomes) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(incomes))
+	for i, income := range incomes {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"registered_account_id": income.RegisteredAccountID,
+				"exchange":              income.Exchange,
+				"market":                income.Market,
+				"tran_id":               income.TranID,
+				"income_type":           income.IncomeType,
+				"asset":                 income.Asset,
+			}).
+			SetUpdate(bson.M{"$set": income}).
+			SetUpsert(true)
+	}
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error":       err,
+			"incomeCount": len(incomes),
+		}).Error("Failed to save incomes")
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"inserted": result.UpsertedCount,
+		"updated":  result.ModifiedCount,
+		"incomes":  len(incomes),
+	}).Info("Successfully saved incomes")
+	return nil
+}
+
+// GetIncomesInRange lấy income của account trong khoảng [start, end), incomeType rỗng là mọi loại
+func (r *IncomeRepository) GetIncomesInRange(ctx context.Context, accountID primitive.ObjectID, incomeType string, start, end time.Time) ([]models.Income, error) {
+	filter := bson.M{
+		"registered_account_id": accountID,
+		"time":                  bson.M{"$gte": start, "$lt": end},
+	}
+	if incomeType != "" {
+		filter["income_type"] = incomeType
+	}
+	var incomes []models.Income
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"time": 1}))
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to find incomes in range")
+		return nil, err
+	}
+	if err := cursor.All(ctx, &incomes); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode incomes")
+		return nil, err
+	}
+	return incomes, nil
+}
+
+// SumIncomes cộng income của account trong khoảng [start, end) theo loại và asset
+func (r *IncomeRepository) SumIncomes(ctx context.Context, accountID primitive.ObjectID, start, end time.Time) ([]models.IncomeTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"registered_account_id": accountID,
+			"time":                  bson.M{"$gte": start, "$lt": end},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"income_type": "$income_type", "asset": "$asset"},
+			"amount": bson.M{"$sum": "$income"},
+			"count":  bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.income_type", Value: 1}, {Key: "_id.asset", Value: 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to aggregate incomes")
+		return nil, err
+	}
+	var rows []struct {
+		Key struct {
+			IncomeType string `bson:"income_type"`
+			Asset      string `bson:"asset"`
+		} `bson:"_id"`
+		Amount models.Decimal `bson:"amount"`
+		Count  int            `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode income totals")
+		return nil, err
+	}
+	totals := make([]models.IncomeTotal, len(rows))
+	for i, row := range rows {
+		totals[i] = models.IncomeTotal{
+			IncomeType: row.Key.IncomeType,
+			Asset:      row.Key.Asset,
+			Amount:     row.Amount.String(),
+			Count:      row.Count,
+		}
+	}
+	return totals, nil
+}
+
+// DeleteIncomesByAccountID xóa toàn bộ income của một tài khoản, trả về số bản ghi đã xóa
+func (r *IncomeRepository) DeleteIncomesByAccountID(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
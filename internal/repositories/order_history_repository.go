@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection chứa fill, dùng để nối order với các lần khớp của order
+const fillCollectionName = "orders"
+
+type OrderHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrderHistoryRepository(client *mongo.Client, dbName, collectionName string) *OrderHistoryRepository {
+	return &OrderHistoryRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveOrderHistory upsert order theo account/exchange/market/symbol và order ID của exchange,
+// order đã lưu được cập nhật trạng thái mới nhất
+func (r *OrderHistoryRepository) SaveOrderHistory(ctx context.Context, orders []models.OrderHistory) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(orders))
+	for i, order := range orders {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"registered_account_id": order.RegisteredAccountID,
+				"exchange":              order.Exchange,
+				"market":                order.Market,
+				"symbol":                order.Symbol,
+				"order_id":              order.OrderID,
+			}).
+			SetUpdate(bson.M{"$set": order}).
+			SetUpsert(true)
+	}
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error":      err,
+			"orderCount": len(orders),
+		}).Error("Failed to save order history")
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"inserted": result.UpsertedCount,
+		"updated":  result.ModifiedCount,
+		"orders":   len(orders),
+	}).Info("Successfully saved order history")
+	return nil
+}
+
+// OrderHistoryFilter là bộ lọc và phân trang của GetOrderHistory. Symbol, Status rỗng và
+// From, To zero là không lọc; Skip/Limit áp dụng sau khi sắp order mới nhất lên trước.
+type OrderHistoryFilter struct {
+	Symbol string
+	Status string
+	From   time.Time
+	To     time.Time
+	Skip   int64
+	Limit  int64
+}
+
+// GetOrderHistory lấy một trang order của các tài khoản trong khoảng [From, To) theo thời gian tạo
+// order, kèm fill của từng order, order mới nhất đứng trước
+func (r *OrderHistoryRepository) GetOrderHistory(ctx context.Context, accountIDs []primitive.ObjectID, filter OrderHistoryFilter) ([]models.OrderHistoryWithFills, error) {
+	match := bson.M{"registered_account_id": bson.M{"$in": accountIDs}}
+	if filter.Symbol != "" {
+		match["symbol"] = filter.Symbol
+	}
+	if filter.Status != "" {
+		match["status"] = filter.Status
+	}
+	timeRange := bson.M{}
+	if !filter.From.IsZero() {
+		timeRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timeRange["$lt"] = filter.To
+	}
+	if len(timeRange) > 0 {
+		match["time"] = timeRange
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "time", Value: -1}, {Key: "order_id", Value: -1}}}},
+		// Phân trang trước $lookup để chỉ nối fill cho các order của trang
+		{{Key: "$skip", Value: filter.Skip}},
+		{{Key: "$limit", Value: filter.Limit}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": fillCollectionName,
+			"let": bson.M{
+				"account":  "$registered_account_id",
+				"exchange": "$exchange",
+				"market":   "$market",
+				"symbol":   "$symbol",
+				"order_id": "$order_id",
+			},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$registered_account_id", "$$account"}},
+					bson.M{"$eq": bson.A{"$exchange", "$$exchange"}},
+					bson.M{"$eq": bson.A{"$market", "$$market"}},
+					bson.M{"$eq": bson.A{"$symbol", "$$symbol"}},
+					bson.M{"$eq": bson.A{"$order_id", "$$order_id"}},
+				}}}},
+				bson.M{"$sort": bson.M{"time": 1}},
+			},
+			"as": "fills",
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to aggregate order history")
+		return nil, err
+	}
+	var orders []models.OrderHistoryWithFills
+	if err := cursor.All(ctx, &orders); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode order history")
+		return nil, err
+	}
+	return orders, nil
+}
+
+// DeleteOrderHistoryByAccountID xóa toàn bộ lịch sử order của một tài khoản, trả về số bản ghi đã xóa
+func (r *OrderHistoryRepository) DeleteOrderHistoryByAccountID(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
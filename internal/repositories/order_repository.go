@@ -3,6 +3,7 @@ package repositories
 import (
 	"autobackcom/internal/models"
 	"context"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -29,23 +30,85 @@ func (r *OrderRepository) SaveOrder(order models.Order) error {
 	return err
 }
 
-// Lấy order mới nhất theo user, exchange, market
-func (r *OrderRepository) GetLatestOrder(ctx context.Context, userID primitive.ObjectID, exchange, market string) (*models.Order, error) {
-	filter := bson.M{
+// Tính cursor theo symbol từ các order đã lưu, dùng cho account đồng bộ từ trước khi có sync_state
+func (r *OrderRepository) GetSymbolCursors(ctx context.Context, userID primitive.ObjectID, exchange, market string) ([]models.SyncState, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"registered_account_id": userID,
+			"exchange":              exchange,
+			"market":                market,
+			"type":                  bson.M{"$ne": models.OrderTypeMarginInterest},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$symbol",
+			// Trade ID không phải số (ví dụ execId của Bybit) được bỏ qua thay vì làm lỗi pipeline
+			"last_trade_id":   bson.M{"$max": bson.M{"$convert": bson.M{"input": "$id", "to": "long", "onError": nil, "onNull": nil}}},
+			"last_trade_time": bson.M{"$max": "$time"},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to aggregate symbol cursors")
+		return nil, err
+	}
+	var rows []struct {
+		Symbol        string    `bson:"_id"`
+		LastTradeID   int64     `bson:"last_trade_id"`
+		LastTradeTime time.Time `bson:"last_trade_time"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode symbol cursors")
+		return nil, err
+	}
+	states := make([]models.SyncState, len(rows))
+	for i, row := range rows {
+		states[i] = models.SyncState{
+			RegisteredAccountID: userID,
+			Exchange:            exchange,
+			Market:              market,
+			Symbol:              row.Symbol,
+			LastTradeID:         row.LastTradeID,
+			LastTradeTime:       row.LastTradeTime,
+		}
+	}
+	return states, nil
+}
+
+// GetSymbols trả về các symbol đã có fill của account trong exchange/market
+func (r *OrderRepository) GetSymbols(ctx context.Context, userID primitive.ObjectID, exchange, market string) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "symbol", bson.M{
 		"registered_account_id": userID,
 		"exchange":              exchange,
 		"market":                market,
-	}
-	opt := options.FindOne().SetSort(bson.D{{"time", -1}})
-	var order models.Order
-	err := r.collection.FindOne(ctx, filter, opt).Decode(&order)
+		"type":                  bson.M{"$ne": models.OrderTypeMarginInterest},
+	})
 	if err != nil {
+		logrus.WithField("error", err).Error("Failed to get order symbols")
 		return nil, err
 	}
-	return &order, nil
+	symbols := make([]string, 0, len(values))
+	for _, value := range values {
+		if symbol, ok := value.(string); ok && symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols, nil
+}
+
+// fillFilter là khóa của một fill: trade ID của exchange trong phạm vi account/market/symbol
+// (trade ID của Binance chỉ duy nhất trong một symbol). Một order khớp nhiều lần có nhiều fill.
+func fillFilter(order models.Order) bson.M {
+	return bson.M{
+		"registered_account_id": order.RegisteredAccountID,
+		"exchange":              order.Exchange,
+		"market":                order.Market,
+		"symbol":                order.Symbol,
+		"id":                    order.ID,
+	}
 }
 
-func (r *OrderRepository) SaveOrders(orders []models.Order) error {
+// SaveOrders upsert các fill theo fillFilter
+func (r *OrderRepository) SaveOrders(ctx context.Context, orders []models.Order) error {
 	if len(orders) == 0 {
 		return nil
 	}
@@ -53,18 +116,14 @@ func (r *OrderRepository) SaveOrders(orders []models.Order) error {
 	models := make([]mongo.WriteModel, len(orders))
 	for i, order := range orders {
 		model := mongo.NewUpdateOneModel().
-			SetFilter(bson.M{
-				"order_id": order.OrderID,
-				"exchange": order.Exchange,
-				"market":   order.Market,
-			}).
+			SetFilter(fillFilter(order)).
 			SetUpdate(bson.M{"$set": order}).
 			SetUpsert(true)
 		models[i] = model
 	}
 
 	opts := options.BulkWrite().SetOrdered(false) // Unordered để tiếp tục khi có lỗi
-	result, err := r.collection.BulkWrite(context.Background(), models, opts)
+	result, err := r.collection.BulkWrite(ctx, models, opts)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"error":      err,
@@ -91,8 +150,13 @@ func (r *OrderRepository) SaveOrders(orders []models.Order) error {
 }
 
 func (r *OrderRepository) GetOrdersByUserID(userID primitive.ObjectID) ([]models.Order, error) {
+	return r.GetOrdersByAccountIDs([]primitive.ObjectID{userID})
+}
+
+// Lấy order của nhiều tài khoản, dùng khi một user có nhiều tài khoản exchange
+func (r *OrderRepository) GetOrdersByAccountIDs(accountIDs []primitive.ObjectID) ([]models.Order, error) {
 	var orders []models.Order
-	cursor, err := r.collection.Find(context.Background(), bson.M{"registered_account_id": userID})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"registered_account_id": bson.M{"$in": accountIDs}})
 	if err != nil {
 		logrus.WithField("error", err).Error("Failed to find orders by userID")
 		return nil, err
@@ -104,3 +168,108 @@ func (r *OrderRepository) GetOrdersByUserID(userID primitive.ObjectID) ([]models
 	}
 	return orders, nil
 }
+
+// GetAggregatedOrders gộp các fill đã lưu của các tài khoản thành order theo order_id,
+// bỏ qua bản ghi không phải trade (lãi vay margin); order khớp gần nhất đứng trước
+func (r *OrderRepository) GetAggregatedOrders(ctx context.Context, accountIDs []primitive.ObjectID) ([]models.AggregatedOrder, error) {
+	orderKey := bson.M{
+		"registered_account_id": "$_id.registered_account_id",
+		"exchange":              "$_id.exchange",
+		"market":                "$_id.market",
+		"symbol":                "$_id.symbol",
+		"order_id":              "$_id.order_id",
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"registered_account_id": bson.M{"$in": accountIDs},
+			"type":                  bson.M{"$ne": models.OrderTypeMarginInterest},
+		}}},
+		// Gộp theo order và commission asset trước để giữ phí của từng asset
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"registered_account_id": "$registered_account_id",
+				"exchange":              "$exchange",
+				"market":                "$market",
+				"symbol":                "$symbol",
+				"order_id":              "$order_id",
+				"commission_asset":      "$commission_asset",
+			},
+			"side":              bson.M{"$first": "$side"},
+			"position_side":     bson.M{"$first": "$position_side"},
+			"executed_quantity": bson.M{"$sum": "$quantity"},
+			"quote_quantity":    bson.M{"$sum": "$quote_quantity"},
+			"price_quantity":    bson.M{"$sum": bson.M{"$multiply": bson.A{toDecimal("$price"), toDecimal("$quantity")}}},
+			"commission":        bson.M{"$sum": "$commission"},
+			"fill_count":        bson.M{"$sum": 1},
+			"first_fill_time":   bson.M{"$min": "$time"},
+			"last_fill_time":    bson.M{"$max": "$time"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               orderKey,
+			"side":              bson.M{"$first": "$side"},
+			"position_side":     bson.M{"$first": "$position_side"},
+			"executed_quantity": bson.M{"$sum": "$executed_quantity"},
+			"quote_quantity":    bson.M{"$sum": "$quote_quantity"},
+			"price_quantity":    bson.M{"$sum": "$price_quantity"},
+			"commissions":       bson.M{"$push": bson.M{"asset": "$_id.commission_asset", "amount": "$commission"}},
+			"fill_count":        bson.M{"$sum": "$fill_count"},
+			"first_fill_time":   bson.M{"$min": "$first_fill_time"},
+			"last_fill_time":    bson.M{"$max": "$last_fill_time"},
+		}}},
+		// Giá trung bình theo khối lượng Σ(price*quantity)/Σquantity. Không dùng quote_quantity vì với
+		// COIN-M và OKX swap quantity là số hợp đồng còn quote_quantity là notional USD.
+		{{Key: "$set", Value: bson.M{"avg_price": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$executed_quantity", 0}},
+			0,
+			bson.M{"$divide": bson.A{"$price_quantity", "$executed_quantity"}},
+		}}}}},
+		{{Key: "$replaceWith", Value: bson.M{"$mergeObjects": bson.A{"$_id", "$$ROOT"}}}},
+		{{Key: "$unset", Value: "price_quantity"}},
+		{{Key: "$sort", Value: bson.D{{Key: "last_fill_time", Value: -1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to aggregate orders")
+		return nil, err
+	}
+	var orders []models.AggregatedOrder
+	if err := cursor.All(ctx, &orders); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode aggregated orders")
+		return nil, err
+	}
+	return orders, nil
+}
+
+// toDecimal chuyển field sang Decimal128 trong pipeline, dữ liệu cũ lưu dạng chuỗi vẫn tính được
+func toDecimal(field string) bson.M {
+	return bson.M{"$convert": bson.M{"input": field, "to": "decimal", "onError": 0, "onNull": 0}}
+}
+
+// Lấy các order của account trong khoảng [start, end)
+func (r *OrderRepository) GetOrdersInRange(ctx context.Context, userID primitive.ObjectID, start, end time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	filter := bson.M{
+		"registered_account_id": userID,
+		"time":                  bson.M{"$gte": start, "$lt": end},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to find orders in range")
+		return nil, err
+	}
+	err = cursor.All(ctx, &orders)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to decode orders")
+		return nil, err
+	}
+	return orders, nil
+}
+
+// DeleteOrdersByAccountID xóa toàn bộ order của một tài khoản, trả về số order đã xóa
+func (r *OrderRepository) DeleteOrdersByAccountID(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newTestClient kết nối MONGODB_TEST_URI, bỏ qua test khi không có MongoDB
+func newTestClient(t *testing.T) *mongo.Client {
+	t.Helper()
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("mongo.Connect: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	return client
+}
+
+// testCollectionName tạo tên collection riêng cho từng test và xóa collection khi test kết thúc
+func testCollectionName(t *testing.T, client *mongo.Client, prefix string) string {
+	t.Helper()
+	name := prefix + "_" + primitive.NewObjectID().Hex()
+	t.Cleanup(func() { client.Database(testDatabase).Collection(name).Drop(context.Background()) })
+	return name
+}
+
+const testDatabase = "autobackcom_test"
+
+func newTestOrderRepository(t *testing.T) *OrderRepository {
+	client := newTestClient(t)
+	return NewOrderRepository(client, testDatabase, testCollectionName(t, client, "orders"))
+}
+
+func TestGetAggregatedOrdersAvgPrice(t *testing.T) {
+	repo := newTestOrderRepository(t)
+	accountID := primitive.NewObjectID()
+	now := time.Now()
+	fill := func(id, exchange, market, symbol string, orderID int64, price, quantity, quote string) models.Order {
+		return models.Order{
+			ID:                  id,
+			RegisteredAccountID: accountID,
+			Exchange:            exchange,
+			Market:              market,
+			Symbol:              symbol,
+			OrderID:             orderID,
+			Side:                "BUY",
+			Price:               models.DecimalFromString(price),
+			Quantity:            models.DecimalFromString(quantity),
+			QuoteQuantity:       models.DecimalFromString(quote),
+			Commission:          models.DecimalFromString("0"),
+			CommissionAsset:     "USDT",
+			Time:                now,
+		}
+	}
+	fills := []models.Order{
+		// COIN-M: quantity là số hợp đồng 100 USD, quote_quantity là notional USD
+		fill("1", "binance", "coinm", "BTCUSD_PERP", 1, "50000", "10", "1000"),
+		fill("2", "binance", "coinm", "BTCUSD_PERP", 1, "60000", "30", "3000"),
+		// OKX swap: quantity là sz hợp đồng ctVal 0.01, quote_quantity = px*sz*ctVal
+		fill("3", "okx", "swap", "BTC-USDT-SWAP", 2, "100", "2", "2"),
+		fill("4", "okx", "swap", "BTC-USDT-SWAP", 2, "200", "6", "12"),
+	}
+	for _, order := range fills {
+		if err := repo.SaveOrder(order); err != nil {
+			t.Fatalf("SaveOrder: %v", err)
+		}
+	}
+	orders, err := repo.GetAggregatedOrders(context.Background(), []primitive.ObjectID{accountID})
+	if err != nil {
+		t.Fatalf("GetAggregatedOrders: %v", err)
+	}
+	want := map[string]string{
+		"BTCUSD_PERP":   "57500",
+		"BTC-USDT-SWAP": "175",
+	}
+	if len(orders) != len(want) {
+		t.Fatalf("got %d aggregated orders, want %d", len(orders), len(want))
+	}
+	for _, order := range orders {
+		if !order.AvgPrice.Equal(models.DecimalFromString(want[order.Symbol]).Decimal) {
+			t.Errorf("%s avg price = %s, want %s", order.Symbol, order.AvgPrice, want[order.Symbol])
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PriceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceRepository(client *mongo.Client, dbName, collectionName string) *PriceRepository {
+	return &PriceRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// GetPrice lấy giá đã cache, trả về mongo.ErrNoDocuments nếu chưa có
+func (r *PriceRepository) GetPrice(ctx context.Context, asset, quote string, at time.Time) (models.Price, error) {
+	var price models.Price
+	err := r.collection.FindOne(ctx, bson.M{"asset": asset, "quote": quote, "time": at}).Decode(&price)
+	return price, err
+}
+
+func (r *PriceRepository) SavePrice(ctx context.Context, price models.Price) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"asset": price.Asset, "quote": price.Quote, "time": price.Time},
+		bson.M{"$set": price},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
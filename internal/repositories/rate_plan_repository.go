@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RatePlanRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRatePlanRepository(client *mongo.Client, dbName, collectionName string) *RatePlanRepository {
+	return &RatePlanRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+func (r *RatePlanRepository) SaveRatePlan(ctx context.Context, plan models.RatePlan) error {
+	_, err := r.collection.InsertOne(ctx, plan)
+	return err
+}
+
+// UpdateRatePlan ghi đè gói hoàn phí, trả về mongo.ErrNoDocuments nếu không tồn tại
+func (r *RatePlanRepository) UpdateRatePlan(ctx context.Context, plan models.RatePlan) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": plan.ID}, plan)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// DeleteRatePlan xóa gói hoàn phí, trả về mongo.ErrNoDocuments nếu không tồn tại
+func (r *RatePlanRepository) DeleteRatePlan(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *RatePlanRepository) GetRatePlan(ctx context.Context, id primitive.ObjectID) (models.RatePlan, error) {
+	var plan models.RatePlan
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&plan)
+	return plan, err
+}
+
+func (r *RatePlanRepository) GetAllRatePlans(ctx context.Context) ([]models.RatePlan, error) {
+	var plans []models.RatePlan
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &plans)
+	return plans, err
+}
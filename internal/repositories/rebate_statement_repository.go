@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RebateStatementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRebateStatementRepository(client *mongo.Client, dbName, collectionName string) *RebateStatementRepository {
+	return &RebateStatementRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveStatement lưu statement, tính lại cùng account và kỳ thì ghi đè statement cũ.
+// Trả về statement đã lưu (giữ _id của statement cũ nếu có).
+func (r *RebateStatementRepository) SaveStatement(ctx context.Context, statement models.RebateStatement) (models.RebateStatement, error) {
+	filter := bson.M{
+		"registered_account_id": statement.RegisteredAccountID,
+		"period_start":          statement.PeriodStart,
+		"period_end":            statement.PeriodEnd,
+	}
+	// _id không được thay đổi khi ghi đè nên chỉ set khi insert
+	raw, err := bson.Marshal(statement)
+	if err != nil {
+		return statement, err
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return statement, err
+	}
+	delete(fields, "_id")
+	update := bson.M{
+		"$set":         fields,
+		"$setOnInsert": bson.M{"_id": statement.ID},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var saved models.RebateStatement
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opt).Decode(&saved)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to save rebate statement")
+		return statement, err
+	}
+	return saved, nil
+}
+
+// HasOverlappingStatement kiểm tra account đã có statement với kỳ giao với [start, end) hay chưa,
+// statement có đúng kỳ [start, end) không tính vì sẽ được ghi đè khi tính lại
+func (r *RebateStatementRepository) HasOverlappingStatement(ctx context.Context, accountID primitive.ObjectID, start, end time.Time) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"registered_account_id": accountID,
+		"period_start":          bson.M{"$lt": end},
+		"period_end":            bson.M{"$gt": start},
+		"$nor": []bson.M{
+			{"period_start": start, "period_end": end},
+		},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to count overlapping rebate statements")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetStatementsByAccountID lấy các statement của account, kỳ mới nhất trước
+func (r *RebateStatementRepository) GetStatementsByAccountID(ctx context.Context, accountID primitive.ObjectID) ([]models.RebateStatement, error) {
+	var statements []models.RebateStatement
+	opt := options.Find().SetSort(bson.D{{Key: "period_start", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"registered_account_id": accountID}, opt)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to find rebate statements")
+		return nil, err
+	}
+	err = cursor.All(ctx, &statements)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to decode rebate statements")
+		return nil, err
+	}
+	return statements, nil
+}
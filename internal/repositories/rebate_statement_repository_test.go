@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHasOverlappingStatement(t *testing.T) {
+	client := newTestClient(t)
+	repo := NewRebateStatementRepository(client, testDatabase, testCollectionName(t, client, "rebate_statements"))
+	ctx := context.Background()
+	accountID := primitive.NewObjectID()
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	// Statement đã có cho kỳ [1/1, 8/1)
+	if _, err := repo.SaveStatement(ctx, models.RebateStatement{
+		ID:                  primitive.NewObjectID(),
+		RegisteredAccountID: accountID,
+		PeriodStart:         day(1),
+		PeriodEnd:           day(8),
+	}); err != nil {
+		t.Fatalf("SaveStatement: %v", err)
+	}
+	tests := []struct {
+		name      string
+		accountID primitive.ObjectID
+		start     time.Time
+		end       time.Time
+		overlaps  bool
+	}{
+		{name: "same period is recalculated", accountID: accountID, start: day(1), end: day(8), overlaps: false},
+		{name: "adjacent period", accountID: accountID, start: day(8), end: day(15), overlaps: false},
+		{name: "period before", accountID: accountID, start: day(1).AddDate(0, 0, -7), end: day(1), overlaps: false},
+		{name: "partial overlap", accountID: accountID, start: day(5), end: day(12), overlaps: true},
+		{name: "contained period", accountID: accountID, start: day(2), end: day(3), overlaps: true},
+		{name: "covering period", accountID: accountID, start: day(1), end: day(31), overlaps: true},
+		{name: "other account", accountID: primitive.NewObjectID(), start: day(5), end: day(12), overlaps: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlaps, err := repo.HasOverlappingStatement(ctx, tt.accountID, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("HasOverlappingStatement: %v", err)
+			}
+			if overlaps != tt.overlaps {
+				t.Errorf("overlaps = %v, want %v", overlaps, tt.overlaps)
+			}
+		})
+	}
+}
@@ -29,6 +29,99 @@ func (r *RegisteredAccountRepository) UpdateRegisteredAccount(account models.Reg
 	return err
 }
 
+// secretFields là các trường tham chiếu tới API key của tài khoản, luôn được ghi cùng nhau
+// (kể cả giá trị rỗng) để không còn sót dữ liệu của backend cũ
+func secretFields(account models.RegisteredAccount) bson.M {
+	return bson.M{
+		"encrypted_api_key":    account.EncryptedAPIKey,
+		"encrypted_secret":     account.EncryptedSecret,
+		"encrypted_passphrase": account.EncryptedPassphrase,
+		"secret_backend":       account.SecretBackend,
+		"wrapped_data_key":     account.WrappedDataKey,
+		"secret_ref":           account.SecretRef,
+	}
+}
+
+// UpdateCredentials ghi API key mới, quyền của key và trạng thái của tài khoản, tăng
+// credentials_version để các stream đang chạy nhận ra key đã bị thay
+func (r *RegisteredAccountRepository) UpdateCredentials(ctx context.Context, account models.RegisteredAccount) error {
+	set := secretFields(account)
+	set["permissions"] = account.Permissions
+	set["status"] = account.Status
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": account.ID}, bson.M{
+		"$set": set,
+		"$inc": bson.M{"credentials_version": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReplaceEncryptedKeys ghi ciphertext mới cho API key/secret/passphrase (hoặc data key) chỉ khi
+// ciphertext hiện tại vẫn như lúc đọc, tránh ghi đè key vừa được user thay trong lúc đang mã hóa lại.
+// Trả về false nếu tài khoản đã thay đổi.
+func (r *RegisteredAccountRepository) ReplaceEncryptedKeys(ctx context.Context, current, updated models.RegisteredAccount) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":               current.ID,
+			"encrypted_api_key": current.EncryptedAPIKey,
+			"encrypted_secret":  current.EncryptedSecret,
+		},
+		bson.M{"$set": secretFields(updated)},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// UpdateStatus đổi trạng thái đồng bộ của tài khoản, trả về mongo.ErrNoDocuments nếu không tồn tại
+func (r *RegisteredAccountRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"status": status}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateListenKey lưu listen key của user data stream đang mở, rỗng khi stream đã đóng
+func (r *RegisteredAccountRepository) UpdateListenKey(ctx context.Context, id primitive.ObjectID, listenKey string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"listen_key": listenKey}})
+	return err
+}
+
+// ClearListenKey xóa listen key của stream đã đóng nếu document vẫn đang lưu đúng key đó
+func (r *RegisteredAccountRepository) ClearListenKey(ctx context.Context, id primitive.ObjectID, listenKey string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "listen_key": listenKey}, bson.M{"$set": bson.M{"listen_key": ""}})
+	return err
+}
+
+// AssignRatePlan thêm gói hoàn phí cho account, danh sách luôn giữ thứ tự theo effective_from
+func (r *RegisteredAccountRepository) AssignRatePlan(ctx context.Context, accountID primitive.ObjectID, assignment models.RatePlanAssignment) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": accountID}, bson.M{
+		"$push": bson.M{
+			"rate_plans": bson.M{
+				"$each": []models.RatePlanAssignment{assignment},
+				"$sort": bson.M{"effective_from": 1},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 func (r *RegisteredAccountRepository) GetRegisteredAccount(accountID string) (models.RegisteredAccount, error) {
 	var account models.RegisteredAccount
 	id, err := primitive.ObjectIDFromHex(accountID)
@@ -48,3 +141,25 @@ func (r *RegisteredAccountRepository) GetAllRegisteredAccounts(ctx context.Conte
 	err = cursor.All(ctx, &accounts)
 	return accounts, err
 }
+
+func (r *RegisteredAccountRepository) GetRegisteredAccountsByUserID(ctx context.Context, userID primitive.ObjectID) ([]models.RegisteredAccount, error) {
+	var accounts []models.RegisteredAccount
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &accounts)
+	return accounts, err
+}
+
+// DeleteRegisteredAccount xóa tài khoản, trả về mongo.ErrNoDocuments nếu không tồn tại
+func (r *RegisteredAccountRepository) DeleteRegisteredAccount(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
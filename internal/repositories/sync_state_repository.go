@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mã lỗi IllegalOperation MongoDB trả về khi dùng transaction trên server standalone
+const illegalOperationCode = 20
+
+type SyncStateRepository struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+	// noTransactions bật khi MongoDB không hỗ trợ transaction (standalone), từ đó ghi trực tiếp
+	noTransactions atomic.Bool
+}
+
+func NewSyncStateRepository(client *mongo.Client, dbName, collectionName string) *SyncStateRepository {
+	return &SyncStateRepository{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+func syncStateFilter(accountID primitive.ObjectID, exchange, market, symbol string) bson.M {
+	return bson.M{
+		"registered_account_id": accountID,
+		"exchange":              exchange,
+		"market":                market,
+		"symbol":                symbol,
+	}
+}
+
+// WithTransaction chạy fn trong một transaction, các repository dùng ctx được truyền vào fn
+// sẽ ghi cùng một transaction. Transaction cần MongoDB chạy dạng replica set; với server standalone
+// fn được chạy không có transaction (cursor có thể được lưu dù dữ liệu ghi lỗi giữa chừng).
+func (r *SyncStateRepository) WithTransaction(ctx context.Context, fn func(txCtx context.Context) error) error {
+	if r.noTransactions.Load() {
+		return fn(ctx)
+	}
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	if isTransactionNotSupported(err) {
+		if r.noTransactions.CompareAndSwap(false, true) {
+			logrus.Warn("MongoDB does not support transactions (not a replica set), writing without transactions")
+		}
+		return fn(ctx)
+	}
+	return err
+}
+
+// isTransactionNotSupported nhận lỗi của server standalone khi bắt đầu transaction,
+// lúc đó chưa có dữ liệu nào được ghi nên chạy lại không transaction là an toàn
+func isTransactionNotSupported(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(illegalOperationCode) && serverErr.HasErrorMessage("Transaction numbers are only allowed on a replica set member or mongos")
+}
+
+// GetStates lấy toàn bộ trạng thái đồng bộ (theo symbol và của cả market) của một account
+func (r *SyncStateRepository) GetStates(ctx context.Context, accountID primitive.ObjectID, exchange, market string) ([]models.SyncState, error) {
+	var states []models.SyncState
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"registered_account_id": accountID,
+		"exchange":              exchange,
+		"market":                market,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &states)
+	return states, err
+}
+
+// SaveCursors cập nhật cursor theo symbol, dùng $max để cursor không bị lùi
+func (r *SyncStateRepository) SaveCursors(ctx context.Context, states []models.SyncState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, len(states))
+	for i, state := range states {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(syncStateFilter(state.RegisteredAccountID, state.Exchange, state.Market, state.Symbol)).
+			SetUpdate(bson.M{
+				"$max": bson.M{
+					"last_trade_id":   state.LastTradeID,
+					"last_trade_time": state.LastTradeTime,
+				},
+				"$set": bson.M{"updated_at": now},
+			}).
+			SetUpsert(true)
+	}
+	_, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// MarkSuccess ghi nhận lần đồng bộ thành công của cả market và mốc thời gian đã đồng bộ tới
+func (r *SyncStateRepository) MarkSuccess(ctx context.Context, accountID primitive.ObjectID, exchange, market string, syncedUntil time.Time) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		syncStateFilter(accountID, exchange, market, ""),
+		bson.M{
+			"$set": bson.M{
+				"synced_until":    syncedUntil,
+				"last_success_at": now,
+				"last_error":      "",
+				"updated_at":      now,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// MarkFailure ghi nhận lỗi của lần đồng bộ gần nhất, không thay đổi cursor
+func (r *SyncStateRepository) MarkFailure(ctx context.Context, accountID primitive.ObjectID, exchange, market string, syncErr error) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		syncStateFilter(accountID, exchange, market, ""),
+		bson.M{
+			"$set": bson.M{
+				"last_failure_at": now,
+				"last_error":      syncErr.Error(),
+				"updated_at":      now,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// DeleteStates xóa toàn bộ trạng thái đồng bộ của một tài khoản
+func (r *SyncStateRepository) DeleteStates(ctx context.Context, accountID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	return err
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TransferRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTransferRepository(client *mongo.Client, dbName, collectionName string) *TransferRepository {
+	return &TransferRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveTransfers upsert nạp/rút/chuyển tiền theo account/exchange/loại và ID của exchange,
+// bản ghi đồng bộ lại sẽ cập nhật trạng thái mới nhất
+func (r *TransferRepository) SaveTransfers(ctx context.Context, transfers []models.Transfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, len(transfers))
+	for i, transfer := range transfers {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"registered_account_id": transfer.RegisteredAccountID,
+				"exchange":              transfer.Exchange,
+				"type":                  transfer.Type,
+				"transfer_id":           transfer.TransferID,
+			}).
+			SetUpdate(bson.M{"$set": transfer}).
+			SetUpsert(true)
+	}
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error":         err,
+			"transferCount": len(transfers),
+		}).Error("Failed to save transfers")
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"inserted":  result.UpsertedCount,
+		"updated":   result.ModifiedCount,
+		"transfers": len(transfers),
+	}).Info("Successfully saved transfers")
+	return nil
+}
+
+// GetTransfersInRange lấy nạp/rút/chuyển tiền của account trong khoảng [start, end), transferType rỗng là mọi loại
+func (r *TransferRepository) GetTransfersInRange(ctx context.Context, accountID primitive.ObjectID, transferType string, start, end time.Time) ([]models.Transfer, error) {
+	filter := bson.M{
+		"registered_account_id": accountID,
+		"time":                  bson.M{"$gte": start, "$lt": end},
+	}
+	if transferType != "" {
+		filter["type"] = transferType
+	}
+	var transfers []models.Transfer
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"time": 1}))
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to find transfers in range")
+		return nil, err
+	}
+	if err := cursor.All(ctx, &transfers); err != nil {
+		logrus.WithField("error", err).Error("Failed to decode transfers")
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// DeleteTransfersByAccountID xóa toàn bộ nạp/rút/chuyển tiền của một tài khoản, trả về số bản ghi đã xóa
+func (r *TransferRepository) DeleteTransfersByAccountID(ctx context.Context, accountID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"registered_account_id": accountID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
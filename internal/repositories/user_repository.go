@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"autobackcom/internal/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserRepository(client *mongo.Client, dbName, collectionName string) *UserRepository {
+	return &UserRepository{
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+func (r *UserRepository) SaveUser(ctx context.Context, user models.User) error {
+	_, err := r.collection.InsertOne(ctx, user)
+	return err
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	return user, err
+}
+
+func (r *UserRepository) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, bson.M{"username": username}).Decode(&user)
+	return user, err
+}
+
+// UpdateRole đổi role của user, trả về mongo.ErrNoDocuments nếu không tồn tại
+func (r *UserRepository) UpdateRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/utils"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+const dataKeySize = 32
+
+// EnvelopeStore mã hóa mỗi tài khoản bằng một data key riêng, data key được mã hóa (wrap)
+// bằng master key của keyring. Xoay master key chỉ cần wrap lại data key.
+type EnvelopeStore struct {
+	keyring Keyring
+}
+
+func NewEnvelopeStore(keyring Keyring) *EnvelopeStore {
+	return &EnvelopeStore{keyring: keyring}
+}
+
+func (s *EnvelopeStore) Name() string {
+	return BackendEnvelope
+}
+
+func (s *EnvelopeStore) Seal(ctx context.Context, account *models.RegisteredAccount, creds Credentials) error {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return err
+	}
+	wrapped, err := s.keyring.Encrypt(base64.StdEncoding.EncodeToString(dataKey))
+	if err != nil {
+		return err
+	}
+	fields, err := encryptFields(func(value string) (string, error) {
+		return utils.EncryptWithKey(dataKey, value)
+	}, creds)
+	if err != nil {
+		return err
+	}
+	account.EncryptedAPIKey, account.EncryptedSecret, account.EncryptedPassphrase = fields[0], fields[1], fields[2]
+	account.WrappedDataKey = wrapped
+	return nil
+}
+
+func (s *EnvelopeStore) Open(ctx context.Context, account models.RegisteredAccount) (Credentials, error) {
+	if account.WrappedDataKey == "" {
+		return Credentials{}, errors.New("account has no wrapped data key")
+	}
+	encodedKey, err := s.keyring.Decrypt(account.WrappedDataKey)
+	if err != nil {
+		return Credentials{}, err
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return decryptFields(func(value string) (string, error) {
+		return utils.DecryptWithKey(dataKey, value)
+	}, account)
+}
+
+// Delete không cần làm gì vì data key nằm trong document tài khoản
+func (s *EnvelopeStore) Delete(ctx context.Context, account models.RegisteredAccount) error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEnvelopeStoreRoundTrip(t *testing.T) {
+	store := NewEnvelopeStore(testKeyring(t, testKeyV1, ""))
+	creds := Credentials{APIKey: "api-key", Secret: "api-secret", Passphrase: "pass"}
+	first := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	second := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	for _, account := range []*models.RegisteredAccount{&first, &second} {
+		if err := store.Seal(context.Background(), account, creds); err != nil {
+			t.Fatalf("Seal: %v", err)
+		}
+		opened, err := store.Open(context.Background(), *account)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if opened != creds {
+			t.Errorf("Open = %+v, want %+v", opened, creds)
+		}
+	}
+	if first.WrappedDataKey == "" || first.WrappedDataKey == second.WrappedDataKey {
+		t.Error("each account must get its own wrapped data key")
+	}
+}
+
+func TestEnvelopeStoreOpenAfterMasterKeyRotation(t *testing.T) {
+	creds := Credentials{APIKey: "api-key", Secret: "api-secret"}
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := NewEnvelopeStore(testKeyring(t, testKeyV1, "")).Seal(context.Background(), &account, creds); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	rotated := NewEnvelopeStore(testKeyring(t, testKeyV1+","+testKeyV2, "v2"))
+	opened, err := rotated.Open(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if opened != creds {
+		t.Errorf("Open = %+v, want %+v", opened, creds)
+	}
+}
+
+func TestEnvelopeStoreOpenErrors(t *testing.T) {
+	store := NewEnvelopeStore(testKeyring(t, testKeyV1, ""))
+	if _, err := store.Open(context.Background(), models.RegisteredAccount{EncryptedAPIKey: "v1:abc"}); err == nil {
+		t.Error("expected error for account without wrapped data key")
+	}
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := store.Seal(context.Background(), &account, Credentials{APIKey: "k", Secret: "s"}); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if _, err := NewEnvelopeStore(testKeyring(t, testKeyV2, "")).Open(context.Background(), account); err == nil {
+		t.Error("expected error unwrapping data key with a different master key")
+	}
+}
@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"context"
+)
+
+// Keyring mã hóa/giải mã bằng master key có phiên bản (utils.Keyring)
+type Keyring interface {
+	Encrypt(plainText string) (string, error)
+	Decrypt(cipherText string) (string, error)
+}
+
+// MongoStore mã hóa AES-GCM từng trường bằng keyring và lưu ngay trong document registered_accounts
+type MongoStore struct {
+	keyring Keyring
+}
+
+func NewMongoStore(keyring Keyring) *MongoStore {
+	return &MongoStore{keyring: keyring}
+}
+
+func (s *MongoStore) Name() string {
+	return BackendMongo
+}
+
+func (s *MongoStore) Seal(ctx context.Context, account *models.RegisteredAccount, creds Credentials) error {
+	fields, err := encryptFields(s.keyring.Encrypt, creds)
+	if err != nil {
+		return err
+	}
+	account.EncryptedAPIKey, account.EncryptedSecret, account.EncryptedPassphrase = fields[0], fields[1], fields[2]
+	return nil
+}
+
+func (s *MongoStore) Open(ctx context.Context, account models.RegisteredAccount) (Credentials, error) {
+	return decryptFields(s.keyring.Decrypt, account)
+}
+
+// Delete không cần làm gì vì secret nằm trong document tài khoản
+func (s *MongoStore) Delete(ctx context.Context, account models.RegisteredAccount) error {
+	return nil
+}
+
+// encryptFields mã hóa key, secret và passphrase (bỏ qua passphrase rỗng)
+func encryptFields(encrypt func(string) (string, error), creds Credentials) ([3]string, error) {
+	var fields [3]string
+	for i, value := range []string{creds.APIKey, creds.Secret, creds.Passphrase} {
+		if value == "" {
+			continue
+		}
+		encrypted, err := encrypt(value)
+		if err != nil {
+			return fields, err
+		}
+		fields[i] = encrypted
+	}
+	return fields, nil
+}
+
+func decryptFields(decrypt func(string) (string, error), account models.RegisteredAccount) (Credentials, error) {
+	var values [3]string
+	for i, field := range []string{account.EncryptedAPIKey, account.EncryptedSecret, account.EncryptedPassphrase} {
+		if field == "" {
+			continue
+		}
+		value, err := decrypt(field)
+		if err != nil {
+			return Credentials{}, err
+		}
+		values[i] = value
+	}
+	return Credentials{APIKey: values[0], Secret: values[1], Passphrase: values[2]}, nil
+}
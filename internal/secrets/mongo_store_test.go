@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/utils"
+	"context"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testKeyring dựng keyring với các key 32 byte theo ENCRYPTION_KEYS
+func testKeyring(t *testing.T, keys, activeID string) *utils.Keyring {
+	t.Helper()
+	ring, err := utils.LoadKeyring(keys, activeID, "")
+	if err != nil {
+		t.Fatalf("LoadKeyring: %v", err)
+	}
+	return ring
+}
+
+const (
+	testKeyV1 = "v1:0123456789abcdef0123456789abcdef"
+	testKeyV2 = "v2:fedcba9876543210fedcba9876543210"
+)
+
+func TestMongoStoreRoundTrip(t *testing.T) {
+	store := NewMongoStore(testKeyring(t, testKeyV1, ""))
+	creds := Credentials{APIKey: "api-key", Secret: "api-secret", Passphrase: "pass"}
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := store.Seal(context.Background(), &account, creds); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	for _, field := range []string{account.EncryptedAPIKey, account.EncryptedSecret, account.EncryptedPassphrase} {
+		if !strings.HasPrefix(field, "v1:") {
+			t.Errorf("field %q is not encrypted with the active key", field)
+		}
+	}
+	opened, err := store.Open(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if opened != creds {
+		t.Errorf("Open = %+v, want %+v", opened, creds)
+	}
+}
+
+func TestMongoStoreEmptyPassphrase(t *testing.T) {
+	store := NewMongoStore(testKeyring(t, testKeyV1, ""))
+	creds := Credentials{APIKey: "api-key", Secret: "api-secret"}
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := store.Seal(context.Background(), &account, creds); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if account.EncryptedPassphrase != "" {
+		t.Errorf("EncryptedPassphrase = %q, want empty", account.EncryptedPassphrase)
+	}
+	opened, err := store.Open(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if opened != creds {
+		t.Errorf("Open = %+v, want %+v", opened, creds)
+	}
+}
+
+func TestMongoStoreOpenWithUnknownKey(t *testing.T) {
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := NewMongoStore(testKeyring(t, testKeyV1, "")).Seal(context.Background(), &account, Credentials{APIKey: "k", Secret: "s"}); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if _, err := NewMongoStore(testKeyring(t, testKeyV2, "")).Open(context.Background(), account); err == nil {
+		t.Error("expected error opening with a keyring that lacks the sealing key")
+	}
+}
@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"os"
+)
+
+// Tên các backend, được ghi vào RegisteredAccount.SecretBackend
+const (
+	BackendMongo    = "mongo"
+	BackendEnvelope = "envelope"
+	BackendVault    = "vault"
+)
+
+// Credentials là API key/secret/passphrase chưa mã hóa của một tài khoản
+type Credentials struct {
+	APIKey     string
+	Secret     string
+	Passphrase string
+}
+
+// SecretStore lưu và đọc credentials của tài khoản exchange. Seal chỉ cập nhật các trường
+// tham chiếu trên account, việc lưu account vào Mongo do caller thực hiện.
+type SecretStore interface {
+	Name() string
+	Seal(ctx context.Context, account *models.RegisteredAccount, creds Credentials) error
+	Open(ctx context.Context, account models.RegisteredAccount) (Credentials, error)
+	Delete(ctx context.Context, account models.RegisteredAccount) error
+}
+
+// Router ghi bằng backend đang chọn và đọc bằng backend đã ghi tài khoản,
+// nhờ vậy đổi backend không làm hỏng các tài khoản đã lưu trước đó
+type Router struct {
+	active   SecretStore
+	backends map[string]SecretStore
+}
+
+func NewRouter(active SecretStore, others ...SecretStore) *Router {
+	router := &Router{active: active, backends: map[string]SecretStore{active.Name(): active}}
+	for _, store := range others {
+		router.backends[store.Name()] = store
+	}
+	return router
+}
+
+func (r *Router) Name() string {
+	return r.active.Name()
+}
+
+func (r *Router) Seal(ctx context.Context, account *models.RegisteredAccount, creds Credentials) error {
+	// Xóa tham chiếu của backend cũ để Open không đọc nhầm
+	account.EncryptedAPIKey = ""
+	account.EncryptedSecret = ""
+	account.EncryptedPassphrase = ""
+	account.WrappedDataKey = ""
+	account.SecretRef = ""
+	if err := r.active.Seal(ctx, account, creds); err != nil {
+		return err
+	}
+	account.SecretBackend = r.active.Name()
+	return nil
+}
+
+func (r *Router) Open(ctx context.Context, account models.RegisteredAccount) (Credentials, error) {
+	store, err := r.backendOf(account)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return store.Open(ctx, account)
+}
+
+func (r *Router) Delete(ctx context.Context, account models.RegisteredAccount) error {
+	store, err := r.backendOf(account)
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, account)
+}
+
+func (r *Router) backendOf(account models.RegisteredAccount) (SecretStore, error) {
+	name := account.SecretBackend
+	if name == "" {
+		name = BackendMongo
+	}
+	store, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("secret backend %q is not configured", name)
+	}
+	return store, nil
+}
+
+// NewSecretStoreFromEnv dựng SecretStore theo SECRET_STORE (mongo, envelope, vault; mặc định mongo).
+// Backend mongo và envelope luôn được bật để đọc tài khoản cũ, vault chỉ bật khi có VAULT_ADDR.
+func NewSecretStoreFromEnv(keyring Keyring) (*Router, error) {
+	mongoStore := NewMongoStore(keyring)
+	envelopeStore := NewEnvelopeStore(keyring)
+	stores := map[string]SecretStore{
+		BackendMongo:    mongoStore,
+		BackendEnvelope: envelopeStore,
+	}
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		vaultStore, err := NewVaultStore(VaultConfig{
+			Address:    addr,
+			Token:      os.Getenv("VAULT_TOKEN"),
+			Namespace:  os.Getenv("VAULT_NAMESPACE"),
+			Mount:      os.Getenv("VAULT_MOUNT"),
+			PathPrefix: os.Getenv("VAULT_PATH_PREFIX"),
+		})
+		if err != nil {
+			return nil, err
+		}
+		stores[BackendVault] = vaultStore
+	}
+	name := os.Getenv("SECRET_STORE")
+	if name == "" {
+		name = BackendMongo
+	}
+	active, ok := stores[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown or unconfigured SECRET_STORE %q", name)
+	}
+	others := make([]SecretStore, 0, len(stores))
+	for _, store := range stores {
+		others = append(others, store)
+	}
+	return NewRouter(active, others...), nil
+}
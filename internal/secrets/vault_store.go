@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVaultMount      = "secret"
+	defaultVaultPathPrefix = "autobackcom/accounts"
+)
+
+// VaultConfig cấu hình backend tương thích HTTP API KV v2 của Vault
+type VaultConfig struct {
+	Address    string // VAULT_ADDR, ví dụ http://127.0.0.1:8200
+	Token      string // VAULT_TOKEN
+	Namespace  string // VAULT_NAMESPACE (Vault Enterprise), có thể bỏ trống
+	Mount      string // Mount của KV v2, mặc định "secret"
+	PathPrefix string // Thư mục chứa secret của các tài khoản
+}
+
+// VaultStore lưu credentials ở Vault KV v2 (hoặc server giả lập cùng API khi chạy local),
+// document tài khoản chỉ giữ đường dẫn secret
+type VaultStore struct {
+	config     VaultConfig
+	httpClient *http.Client
+}
+
+func NewVaultStore(config VaultConfig) (*VaultStore, error) {
+	if config.Address == "" {
+		return nil, errors.New("vault address is required")
+	}
+	if config.Token == "" {
+		return nil, errors.New("VAULT_TOKEN is not set")
+	}
+	if config.Mount == "" {
+		config.Mount = defaultVaultMount
+	}
+	if config.PathPrefix == "" {
+		config.PathPrefix = defaultVaultPathPrefix
+	}
+	config.Address = strings.TrimRight(config.Address, "/")
+	config.Mount = strings.Trim(config.Mount, "/")
+	config.PathPrefix = strings.Trim(config.PathPrefix, "/")
+	return &VaultStore{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *VaultStore) Name() string {
+	return BackendVault
+}
+
+type vaultSecretData struct {
+	APIKey     string `json:"api_key"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+func (s *VaultStore) Seal(ctx context.Context, account *models.RegisteredAccount, creds Credentials) error {
+	if account.ID.IsZero() {
+		return errors.New("account id is required to store secret in vault")
+	}
+	ref := s.config.PathPrefix + "/" + account.ID.Hex()
+	body := map[string]interface{}{
+		"data": vaultSecretData{APIKey: creds.APIKey, Secret: creds.Secret, Passphrase: creds.Passphrase},
+	}
+	if err := s.do(ctx, http.MethodPost, "data/"+ref, body, nil); err != nil {
+		return err
+	}
+	account.SecretRef = ref
+	return nil
+}
+
+func (s *VaultStore) Open(ctx context.Context, account models.RegisteredAccount) (Credentials, error) {
+	if account.SecretRef == "" {
+		return Credentials{}, errors.New("account has no vault secret reference")
+	}
+	var resp struct {
+		Data struct {
+			Data vaultSecretData `json:"data"`
+		} `json:"data"`
+	}
+	if err := s.do(ctx, http.MethodGet, "data/"+account.SecretRef, nil, &resp); err != nil {
+		return Credentials{}, err
+	}
+	data := resp.Data.Data
+	return Credentials{APIKey: data.APIKey, Secret: data.Secret, Passphrase: data.Passphrase}, nil
+}
+
+// Delete xóa mọi phiên bản của secret
+func (s *VaultStore) Delete(ctx context.Context, account models.RegisteredAccount) error {
+	if account.SecretRef == "" {
+		return nil
+	}
+	return s.do(ctx, http.MethodDelete, "metadata/"+account.SecretRef, nil, nil)
+}
+
+func (s *VaultStore) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s", s.config.Address, s.config.Mount, path)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", s.config.Token)
+	if s.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.config.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault %s %s: status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
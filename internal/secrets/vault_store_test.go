@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testVaultToken = "test-token"
+
+// kvServer giả lập API KV v2 của Vault với mount "secret": ghi/đọc data/<path>, xóa metadata/<path>
+type kvServer struct {
+	mu       sync.Mutex
+	secrets  map[string]json.RawMessage
+	requests int
+}
+
+func newKVServer(t *testing.T) (*kvServer, *httptest.Server) {
+	t.Helper()
+	kv := &kvServer{secrets: make(map[string]json.RawMessage)}
+	srv := httptest.NewServer(http.HandlerFunc(kv.serveHTTP))
+	t.Cleanup(srv.Close)
+	return kv, srv
+}
+
+func (kv *kvServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.requests++
+	if r.Header.Get("X-Vault-Token") != testVaultToken {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/secret/")
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "data/"):
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, `{"errors":["invalid body"]}`, http.StatusBadRequest)
+			return
+		}
+		kv.secrets[strings.TrimPrefix(path, "data/")] = body.Data
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"version": 1}})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "data/"):
+		data, ok := kv.secrets[strings.TrimPrefix(path, "data/")]
+		if !ok {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": data}})
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "metadata/"):
+		delete(kv.secrets, strings.TrimPrefix(path, "metadata/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"errors":["unsupported path"]}`, http.StatusNotFound)
+	}
+}
+
+func newTestVaultStore(t *testing.T, address, token string) *VaultStore {
+	t.Helper()
+	store, err := NewVaultStore(VaultConfig{Address: address + "/", Token: token})
+	if err != nil {
+		t.Fatalf("NewVaultStore: %v", err)
+	}
+	return store
+}
+
+func TestVaultStoreSealOpenDelete(t *testing.T) {
+	kv, srv := newKVServer(t)
+	store := newTestVaultStore(t, srv.URL, testVaultToken)
+	creds := Credentials{APIKey: "api-key", Secret: "api-secret", Passphrase: "pass"}
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	if err := store.Seal(context.Background(), &account, creds); err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if want := defaultVaultPathPrefix + "/" + account.ID.Hex(); account.SecretRef != want {
+		t.Errorf("SecretRef = %q, want %q", account.SecretRef, want)
+	}
+	if account.EncryptedAPIKey != "" || account.EncryptedSecret != "" {
+		t.Error("vault store must not keep credentials in the account document")
+	}
+	opened, err := store.Open(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if opened != creds {
+		t.Errorf("Open = %+v, want %+v", opened, creds)
+	}
+	if err := store.Delete(context.Background(), account); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(kv.secrets) != 0 {
+		t.Errorf("secret still stored after Delete: %v", kv.secrets)
+	}
+	if _, err := store.Open(context.Background(), account); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Open after Delete error = %v, want status 404", err)
+	}
+}
+
+func TestVaultStoreErrors(t *testing.T) {
+	kv, srv := newKVServer(t)
+	if _, err := NewVaultStore(VaultConfig{Token: testVaultToken}); err == nil {
+		t.Error("expected error without vault address")
+	}
+	if _, err := NewVaultStore(VaultConfig{Address: srv.URL}); err == nil {
+		t.Error("expected error without vault token")
+	}
+
+	store := newTestVaultStore(t, srv.URL, testVaultToken)
+	if err := store.Seal(context.Background(), &models.RegisteredAccount{}, Credentials{APIKey: "k", Secret: "s"}); err == nil {
+		t.Error("expected error sealing account without id")
+	}
+	if _, err := store.Open(context.Background(), models.RegisteredAccount{ID: primitive.NewObjectID()}); err == nil {
+		t.Error("expected error opening account without secret reference")
+	}
+	if err := store.Delete(context.Background(), models.RegisteredAccount{ID: primitive.NewObjectID()}); err != nil {
+		t.Errorf("Delete without secret reference: %v", err)
+	}
+	if kv.requests != 0 {
+		t.Errorf("made %d vault requests for invalid accounts, want 0", kv.requests)
+	}
+
+	denied := newTestVaultStore(t, srv.URL, "wrong-token")
+	account := models.RegisteredAccount{ID: primitive.NewObjectID()}
+	err := denied.Seal(context.Background(), &account, Credentials{APIKey: "k", Secret: "s"})
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Seal with wrong token error = %v, want status 403", err)
+	}
+	if account.SecretRef != "" {
+		t.Errorf("SecretRef = %q after failed Seal, want empty", account.SecretRef)
+	}
+}
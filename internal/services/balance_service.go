@@ -0,0 +1,145 @@
+package services
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EquityAsset là asset dùng để quy đổi equity theo ngày
+const EquityAsset = "USDT"
+
+type BalanceService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	snapshotRepository          *repositories.BalanceSnapshotRepository
+	clientManager               *ClientManagerService
+	converter                   AssetConverter
+}
+
+// NewBalanceService khởi tạo service, converter phải quy đổi sang EquityAsset
+func NewBalanceService(registeredAccountRepository *repositories.RegisteredAccountRepository, snapshotRepository *repositories.BalanceSnapshotRepository, clientManager *ClientManagerService, converter AssetConverter) *BalanceService {
+	return &BalanceService{
+		registeredAccountRepository: registeredAccountRepository,
+		snapshotRepository:          snapshotRepository,
+		clientManager:               clientManager,
+		converter:                   converter,
+	}
+}
+
+// SnapshotAllBalances chụp số dư cho mọi tài khoản đang active có hỗ trợ snapshot
+func (s *BalanceService) SnapshotAllBalances(ctx context.Context) error {
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	accountsPool := make(chan struct{}, 5)
+	var accountWg sync.WaitGroup
+	for _, account := range accounts {
+		if !account.IsSyncEnabled() {
+			continue
+		}
+		accountsPool <- struct{}{}
+		accountWg.Add(1)
+		go func(accountCopy models.RegisteredAccount) {
+			defer func() {
+				<-accountsPool
+				accountWg.Done()
+			}()
+			err := s.SnapshotBalances(ctx, accountCopy)
+			if err != nil && !errors.Is(err, exchanges.ErrBalancesNotSupported) {
+				log.Println("Snapshot balances error for account", accountCopy.Username, ":", err)
+			}
+		}(account)
+	}
+	accountWg.Wait()
+	return nil
+}
+
+// SnapshotBalances đọc số dư hiện tại của tài khoản và lưu thành một snapshot
+func (s *BalanceService) SnapshotBalances(ctx context.Context, account models.RegisteredAccount) error {
+	fetcher, err := s.clientManager.CreateBalanceFetcher(account)
+	if err != nil {
+		return err
+	}
+	balances, err := fetcher.FetchBalances(ctx)
+	if err != nil {
+		return err
+	}
+	return s.snapshotRepository.SaveSnapshot(ctx, models.BalanceSnapshot{
+		RegisteredAccountID: account.ID,
+		Exchange:            account.Exchange,
+		Market:              account.Market,
+		Time:                time.Now(),
+		Balances:            balances,
+	})
+}
+
+// GetSnapshots lấy snapshot số dư của account trong kỳ [start, end)
+func (s *BalanceService) GetSnapshots(ctx context.Context, account models.RegisteredAccount, start, end time.Time) ([]models.BalanceSnapshot, error) {
+	return s.snapshotRepository.GetSnapshotsInRange(ctx, account.ID, start, end)
+}
+
+// DailyEquity tính equity theo ngày (UTC) trong kỳ [start, end) từ snapshot cuối cùng của mỗi ngày,
+// từng asset được quy đổi sang EquityAsset theo giá tại thời điểm chụp. Ngày không có snapshot bị bỏ qua.
+func (s *BalanceService) DailyEquity(ctx context.Context, account models.RegisteredAccount, start, end time.Time) (*models.EquityCurve, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid period: %s - %s", start, end)
+	}
+	snapshots, err := s.snapshotRepository.GetSnapshotsInRange(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	lastOfDay := make(map[time.Time]models.BalanceSnapshot)
+	for _, snapshot := range snapshots {
+		day := snapshot.Time.UTC().Truncate(24 * time.Hour)
+		if last, ok := lastOfDay[day]; !ok || snapshot.Time.After(last.Time) {
+			lastOfDay[day] = snapshot
+		}
+	}
+	days := make([]time.Time, 0, len(lastOfDay))
+	for day := range lastOfDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	curve := &models.EquityCurve{
+		RegisteredAccountID: account.ID,
+		Asset:               EquityAsset,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		Days:                make([]models.DailyEquity, 0, len(days)),
+	}
+	for _, day := range days {
+		snapshot := lastOfDay[day]
+		equity := decimal.Zero
+		var unconverted []string
+		for _, balance := range snapshot.Balances {
+			if balance.Equity.IsZero() {
+				continue
+			}
+			converted, err := s.converter.ConvertAt(ctx, balance.Asset, balance.Equity.Decimal, snapshot.Time)
+			if err != nil {
+				log.Printf("Convert balance error for account %s, asset %s: %v", account.Username, balance.Asset, err)
+				unconverted = append(unconverted, balance.Asset)
+				continue
+			}
+			equity = equity.Add(converted)
+		}
+		curve.Days = append(curve.Days, models.DailyEquity{
+			Date:         day,
+			SnapshotTime: snapshot.Time,
+			Equity:       equity.String(),
+			Unconverted:  unconverted,
+		})
+	}
+	return curve, nil
+}
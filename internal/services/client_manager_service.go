@@ -2,9 +2,10 @@ package services
 
 import (
 	"autobackcom/internal/exchanges"
-	"autobackcom/internal/exchanges/binance"
 	"autobackcom/internal/models"
-	"autobackcom/internal/utils"
+	"autobackcom/internal/secrets"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -14,6 +15,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrAPIKeyNotReadOnly là lỗi API key có quyền giao dịch, rút tiền hoặc chuyển tiền
+var ErrAPIKeyNotReadOnly = errors.New("api key must be read-only")
+
 // ClientsInfo chứa các client cho một user, theo cặp exchange/market
 type ClientsInfo struct {
 	Clients   map[string]exchanges.ExchangeFetcher // Key: "exchange:market"
@@ -25,13 +29,18 @@ type ClientManagerService struct {
 	clientCache *cache.Cache
 	mutexes     map[string]*sync.RWMutex // Mutex cho mỗi user.ID
 	mutex       sync.RWMutex             // Khóa để quản lý mutexes map
+	secretStore secrets.SecretStore
+	registry    *exchanges.Registry
 }
 
-// NewClientManagerService khởi tạo service
-func NewClientManagerService() *ClientManagerService {
+// NewClientManagerService khởi tạo service, API key của tài khoản được đọc qua secretStore
+// và client được tạo bởi adapter đã đăng ký trong registry
+func NewClientManagerService(secretStore secrets.SecretStore, registry *exchanges.Registry) *ClientManagerService {
 	return &ClientManagerService{
 		clientCache: cache.New(24*time.Hour, 1*time.Hour),
 		mutexes:     make(map[string]*sync.RWMutex),
+		secretStore: secretStore,
+		registry:    registry,
 	}
 }
 
@@ -49,31 +58,107 @@ func (s *ClientManagerService) getUserMutex(userID string) *sync.RWMutex {
 }
 
 // createClient tạo client dựa trên exchange và market
-func createClient(exchange, market string, user models.RegisteredAccount) (exchanges.ExchangeFetcher, error) {
-	apiKey, err := utils.Decrypt(user.EncryptedAPIKey)
+func (s *ClientManagerService) createClient(exchange, market string, user models.RegisteredAccount) (exchanges.ExchangeFetcher, error) {
+	creds, err := s.secretStore.Open(context.Background(), user)
 	if err != nil {
-		log.Printf("Decrypt key error for user %s: %v", user.Username, err)
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
 		return nil, err
 	}
-	secret, err := utils.Decrypt(user.EncryptedSecret)
+	fmt.Println("Creating client for user:", user.Username, "Exchange:", exchange, "Market:", market)
+	return s.registry.NewFetcher(exchange, market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// CreateIncomeFetcher tạo client lấy lịch sử income cho tài khoản, trả về exchanges.ErrIncomeNotSupported
+// nếu exchange/market không hỗ trợ
+func (s *ClientManagerService) CreateIncomeFetcher(user models.RegisteredAccount) (exchanges.IncomeFetcher, error) {
+	if !s.registry.SupportsIncome(user.Exchange, user.Market) {
+		return nil, exchanges.ErrIncomeNotSupported
+	}
+	creds, err := s.secretStore.Open(context.Background(), user)
 	if err != nil {
-		log.Printf("Decrypt secret error for user %s: %v", user.Username, err)
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
 		return nil, err
 	}
-	fmt.Println("Creating client for user:", user.Username, "Exchange:", exchange, "Market:", market)
-	switch exchange {
-	case "binance":
-		switch market {
-		case "spot":
-			return binance.NewBinanceSpotExchange(apiKey, secret, user.IsTestnet), nil
-		case "futures":
-			return binance.NewBinanceFetureExchange(apiKey, secret, user.IsTestnet), nil
-		default:
-			return nil, fmt.Errorf("unsupported market: %s for exchange: %s", market, exchange)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported exchange: %s", exchange)
+	return s.registry.NewIncomeFetcher(user.Exchange, user.Market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// CreateOrderHistoryFetcher tạo client lấy lịch sử order cho tài khoản, trả về
+// exchanges.ErrOrderHistoryNotSupported nếu exchange/market không hỗ trợ
+func (s *ClientManagerService) CreateOrderHistoryFetcher(user models.RegisteredAccount) (exchanges.OrderHistoryFetcher, error) {
+	if !s.registry.SupportsOrderHistory(user.Exchange, user.Market) {
+		return nil, exchanges.ErrOrderHistoryNotSupported
+	}
+	creds, err := s.secretStore.Open(context.Background(), user)
+	if err != nil {
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
+		return nil, err
+	}
+	return s.registry.NewOrderHistoryFetcher(user.Exchange, user.Market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// CreateTransferFetcher tạo client lấy lịch sử nạp/rút/chuyển tiền cho tài khoản, trả về
+// exchanges.ErrTransfersNotSupported nếu exchange/market không hỗ trợ hoặc tài khoản là testnet
+func (s *ClientManagerService) CreateTransferFetcher(user models.RegisteredAccount) (exchanges.TransferFetcher, error) {
+	if user.IsTestnet || !s.registry.SupportsTransfers(user.Exchange, user.Market) {
+		return nil, exchanges.ErrTransfersNotSupported
+	}
+	creds, err := s.secretStore.Open(context.Background(), user)
+	if err != nil {
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
+		return nil, err
+	}
+	return s.registry.NewTransferFetcher(user.Exchange, user.Market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// CreateBalanceFetcher tạo client đọc số dư cho tài khoản, trả về exchanges.ErrBalancesNotSupported
+// nếu exchange/market không hỗ trợ
+func (s *ClientManagerService) CreateBalanceFetcher(user models.RegisteredAccount) (exchanges.BalanceFetcher, error) {
+	if !s.registry.SupportsBalances(user.Exchange, user.Market) {
+		return nil, exchanges.ErrBalancesNotSupported
+	}
+	creds, err := s.secretStore.Open(context.Background(), user)
+	if err != nil {
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
+		return nil, err
+	}
+	return s.registry.NewBalanceFetcher(user.Exchange, user.Market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// SupportsUserStream cho biết exchange/market của tài khoản có user data stream hay không
+func (s *ClientManagerService) SupportsUserStream(user models.RegisteredAccount) bool {
+	return s.registry.SupportsUserStream(user.Exchange, user.Market)
+}
+
+// CreateUserStreamer tạo client user data stream cho tài khoản, trả về
+// exchanges.ErrUserStreamNotSupported nếu exchange/market không hỗ trợ
+func (s *ClientManagerService) CreateUserStreamer(user models.RegisteredAccount) (exchanges.UserStreamer, error) {
+	if !s.registry.SupportsUserStream(user.Exchange, user.Market) {
+		return nil, exchanges.ErrUserStreamNotSupported
+	}
+	creds, err := s.secretStore.Open(context.Background(), user)
+	if err != nil {
+		log.Printf("Open secret error for user %s: %v", user.Username, err)
+		return nil, err
+	}
+	return s.registry.NewUserStreamer(user.Exchange, user.Market, exchanges.Credentials(creds), user.IsTestnet)
+}
+
+// ValidateKey gọi API chỉ đọc có ký để kiểm tra API key trước khi lưu. Key không hợp lệ trả về
+// exchanges.ErrInvalidAPIKey, key có quyền giao dịch/rút tiền trả về ErrAPIKeyNotReadOnly
+// (testnet được bỏ qua kiểm tra quyền vì key testnet luôn có quyền giao dịch).
+func (s *ClientManagerService) ValidateKey(ctx context.Context, exchange, market string, creds secrets.Credentials, isTestnet bool) (models.APIKeyPermissions, error) {
+	client, err := s.registry.NewFetcher(exchange, market, exchanges.Credentials(creds), isTestnet)
+	if err != nil {
+		return models.APIKeyPermissions{}, err
+	}
+	permissions, err := client.KeyPermissions(ctx)
+	if err != nil {
+		return permissions, err
+	}
+	if !isTestnet && permissions.CanMoveFunds() {
+		return permissions, ErrAPIKeyNotReadOnly
 	}
+	return permissions, nil
 }
 
 // GetOrCreateClient lấy hoặc tạo client cho user
@@ -103,7 +188,7 @@ func (s *ClientManagerService) GetOrCreateClient(user models.RegisteredAccount)
 		if _, exists := pair.Clients[clientKey]; exists {
 			return pair, nil
 		}
-		client, err := createClient(user.Exchange, user.Market, user)
+		client, err := s.createClient(user.Exchange, user.Market, user)
 		if err != nil {
 			log.Printf("Create client error for user %s, exchange %s, market %s: %v", user.Username, user.Exchange, user.Market, err)
 			return nil, err
@@ -114,7 +199,7 @@ func (s *ClientManagerService) GetOrCreateClient(user models.RegisteredAccount)
 	}
 
 	// Tạo ClientsInfo mới
-	client, err := createClient(user.Exchange, user.Market, user)
+	client, err := s.createClient(user.Exchange, user.Market, user)
 	if err != nil {
 		log.Printf("Create client error for user %s, exchange %s, market %s: %v", user.Username, user.Exchange, user.Market, err)
 		return nil, err
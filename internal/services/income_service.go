@@ -0,0 +1,130 @@
+package services
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// IncomeSyncMarket là market dùng trong sync_state để lưu mốc đồng bộ income,
+// tách khỏi cursor trade của market futures
+const IncomeSyncMarket = "futures_income"
+
+type IncomeService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	incomeRepository            *repositories.IncomeRepository
+	syncStateRepository         *repositories.SyncStateRepository
+	clientManager               *ClientManagerService
+}
+
+func NewIncomeService(registeredAccountRepository *repositories.RegisteredAccountRepository, incomeRepository *repositories.IncomeRepository, syncStateRepository *repositories.SyncStateRepository, clientManager *ClientManagerService) *IncomeService {
+	return &IncomeService{
+		registeredAccountRepository: registeredAccountRepository,
+		incomeRepository:            incomeRepository,
+		syncStateRepository:         syncStateRepository,
+		clientManager:               clientManager,
+	}
+}
+
+// SyncAllIncomes đồng bộ income cho mọi tài khoản đang active có hỗ trợ income
+func (s *IncomeService) SyncAllIncomes(ctx context.Context) error {
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	accountsPool := make(chan struct{}, 5)
+	var accountWg sync.WaitGroup
+	for _, account := range accounts {
+		if !account.IsSyncEnabled() {
+			continue
+		}
+		accountsPool <- struct{}{}
+		accountWg.Add(1)
+		go func(accountCopy models.RegisteredAccount) {
+			defer func() {
+				<-accountsPool
+				accountWg.Done()
+			}()
+			err := s.SyncIncome(ctx, accountCopy)
+			if err != nil && !errors.Is(err, exchanges.ErrIncomeNotSupported) {
+				log.Println("Sync income error for account", accountCopy.Username, ":", err)
+			}
+		}(account)
+	}
+	accountWg.Wait()
+	return nil
+}
+
+// SyncIncome lấy income mới từ mốc đã đồng bộ lần trước, income và mốc mới được ghi
+// trong cùng transaction để mốc không vượt quá dữ liệu đã lưu
+func (s *IncomeService) SyncIncome(ctx context.Context, account models.RegisteredAccount) error {
+	fetcher, err := s.clientManager.CreateIncomeFetcher(account)
+	if err != nil {
+		if !errors.Is(err, exchanges.ErrIncomeNotSupported) {
+			s.markFailure(ctx, account, err)
+		}
+		return err
+	}
+	states, err := s.syncStateRepository.GetStates(ctx, account.ID, account.Exchange, IncomeSyncMarket)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	for _, state := range states {
+		if state.Symbol == "" {
+			since = state.SyncedUntil
+		}
+	}
+	syncStartedAt := time.Now()
+	incomes, err := fetcher.FetchIncomes(ctx, account.ID, since)
+	if err != nil {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	err = s.syncStateRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.incomeRepository.SaveIncomes(txCtx, incomes); err != nil {
+			return err
+		}
+		return s.syncStateRepository.MarkSuccess(txCtx, account.ID, account.Exchange, IncomeSyncMarket, syncStartedAt)
+	})
+	if err != nil {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	return nil
+}
+
+func (s *IncomeService) markFailure(ctx context.Context, account models.RegisteredAccount, syncErr error) {
+	err := s.syncStateRepository.MarkFailure(ctx, account.ID, account.Exchange, IncomeSyncMarket, syncErr)
+	if err != nil {
+		log.Println("Mark income sync failure error for account", account.Username, ":", err)
+	}
+}
+
+// SummarizeIncomes tổng hợp income của account trong kỳ [start, end) theo loại và asset
+func (s *IncomeService) SummarizeIncomes(ctx context.Context, account models.RegisteredAccount, start, end time.Time) (*models.IncomeSummary, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid period: %s - %s", start, end)
+	}
+	totals, err := s.incomeRepository.SumIncomes(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &models.IncomeSummary{
+		RegisteredAccountID: account.ID,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		Totals:              totals,
+	}, nil
+}
+
+// GetIncomes lấy các bản ghi income của account trong kỳ [start, end), incomeType rỗng là mọi loại
+func (s *IncomeService) GetIncomes(ctx context.Context, account models.RegisteredAccount, incomeType string, start, end time.Time) ([]models.Income, error) {
+	return s.incomeRepository.GetIncomesInRange(ctx, account.ID, incomeType, start, end)
+}
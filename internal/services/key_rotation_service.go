@@ -0,0 +1,93 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"autobackcom/internal/secrets"
+	"autobackcom/internal/utils"
+	"context"
+	"log"
+)
+
+// KeyRotationResult thống kê một lần mã hóa lại secret của các tài khoản
+type KeyRotationResult struct {
+	Total       int
+	Reencrypted int
+	Skipped     int // Đã dùng key active
+	Conflicts   int // Tài khoản bị đổi key trong lúc chạy, lần chạy sau sẽ xử lý
+	Failed      int
+}
+
+// KeyRotationService mã hóa lại API key/secret (hoặc data key) của RegisteredAccount bằng key active của keyring
+type KeyRotationService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	keyring                     *utils.Keyring
+}
+
+func NewKeyRotationService(registeredAccountRepository *repositories.RegisteredAccountRepository, keyring *utils.Keyring) *KeyRotationService {
+	return &KeyRotationService{
+		registeredAccountRepository: registeredAccountRepository,
+		keyring:                     keyring,
+	}
+}
+
+// ReencryptAll mã hóa lại secret của mọi tài khoản chưa dùng key active. Mỗi tài khoản được ghi
+// bằng một update có điều kiện nên service có thể tiếp tục chạy trong lúc mã hóa lại, miễn là
+// service đã được cấu hình keyring chứa cả key cũ và key mới.
+func (s *KeyRotationService) ReencryptAll(ctx context.Context) (KeyRotationResult, error) {
+	var result KeyRotationResult
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, account := range accounts {
+		result.Total++
+		updated, changed, err := s.reencryptAccount(account)
+		if err != nil {
+			log.Printf("Re-encrypt secrets error for account %s: %v", account.ID.Hex(), err)
+			result.Failed++
+			continue
+		}
+		if !changed {
+			result.Skipped++
+			continue
+		}
+		ok, err := s.registeredAccountRepository.ReplaceEncryptedKeys(ctx, account, updated)
+		if err != nil {
+			log.Printf("Save re-encrypted secrets error for account %s: %v", account.ID.Hex(), err)
+			result.Failed++
+			continue
+		}
+		if !ok {
+			result.Conflicts++
+			continue
+		}
+		result.Reencrypted++
+	}
+	return result, nil
+}
+
+// reencryptAccount mã hóa lại theo backend lưu secret: mongo mã hóa lại từng trường,
+// envelope chỉ wrap lại data key, backend ngoài (vault) không chứa dữ liệu do keyring mã hóa
+func (s *KeyRotationService) reencryptAccount(account models.RegisteredAccount) (models.RegisteredAccount, bool, error) {
+	updated := account
+	var fields []*string
+	switch account.SecretBackend {
+	case "", secrets.BackendMongo:
+		fields = []*string{&updated.EncryptedAPIKey, &updated.EncryptedSecret, &updated.EncryptedPassphrase}
+	case secrets.BackendEnvelope:
+		fields = []*string{&updated.WrappedDataKey}
+	default:
+		return account, false, nil
+	}
+	changed := false
+	for _, field := range fields {
+		reencrypted, fieldChanged, err := s.keyring.Reencrypt(*field)
+		if err != nil {
+			return account, false, err
+		}
+		*field = reencrypted
+		changed = changed || fieldChanged
+	}
+	return updated, changed, nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderHistorySyncMarket là market dùng trong sync_state để lưu cursor lịch sử order,
+// tách khỏi cursor trade của cùng market (ví dụ "spot_orders")
+func OrderHistorySyncMarket(market string) string {
+	return market + "_orders"
+}
+
+type OrderHistoryService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	orderRepository             *repositories.OrderRepository
+	orderHistoryRepository      *repositories.OrderHistoryRepository
+	syncStateRepository         *repositories.SyncStateRepository
+	clientManager               *ClientManagerService
+}
+
+func NewOrderHistoryService(registeredAccountRepository *repositories.RegisteredAccountRepository, orderRepository *repositories.OrderRepository, orderHistoryRepository *repositories.OrderHistoryRepository, syncStateRepository *repositories.SyncStateRepository, clientManager *ClientManagerService) *OrderHistoryService {
+	return &OrderHistoryService{
+		registeredAccountRepository: registeredAccountRepository,
+		orderRepository:             orderRepository,
+		orderHistoryRepository:      orderHistoryRepository,
+		syncStateRepository:         syncStateRepository,
+		clientManager:               clientManager,
+	}
+}
+
+// SyncAllOrderHistory đồng bộ lịch sử order cho mọi tài khoản đang active có hỗ trợ lịch sử order
+func (s *OrderHistoryService) SyncAllOrderHistory(ctx context.Context) error {
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	accountsPool := make(chan struct{}, 5)
+	var accountWg sync.WaitGroup
+	for _, account := range accounts {
+		if !account.IsSyncEnabled() {
+			continue
+		}
+		accountsPool <- struct{}{}
+		accountWg.Add(1)
+		go func(accountCopy models.RegisteredAccount) {
+			defer func() {
+				<-accountsPool
+				accountWg.Done()
+			}()
+			err := s.SyncOrderHistory(ctx, accountCopy)
+			if err != nil && !errors.Is(err, exchanges.ErrOrderHistoryNotSupported) {
+				log.Println("Sync order history error for account", accountCopy.Username, ":", err)
+			}
+		}(account)
+	}
+	accountWg.Wait()
+	return nil
+}
+
+// SyncOrderHistory lấy order của các symbol đã có cursor hoặc đã có fill cùng symbol fetcher tự tìm
+// thêm (số dư, vị thế, order mở). Order và cursor mới được
+// ghi trong cùng transaction; cursor dừng trước order chưa kết thúc để lần sau cập nhật trạng thái.
+func (s *OrderHistoryService) SyncOrderHistory(ctx context.Context, account models.RegisteredAccount) error {
+	fetcher, err := s.clientManager.CreateOrderHistoryFetcher(account)
+	if err != nil {
+		if !errors.Is(err, exchanges.ErrOrderHistoryNotSupported) {
+			s.markFailure(ctx, account, err)
+		}
+		return err
+	}
+	syncMarket := OrderHistorySyncMarket(account.Market)
+	states, err := s.syncStateRepository.GetStates(ctx, account.ID, account.Exchange, syncMarket)
+	if err != nil {
+		return err
+	}
+	cursors := make(exchanges.SyncCursors, len(states))
+	for _, state := range states {
+		cursors[state.Symbol] = state
+	}
+	// Symbol đã có fill nhưng chưa có cursor thì lấy order từ đầu
+	symbols, err := s.orderRepository.GetSymbols(ctx, account.ID, account.Exchange, account.Market)
+	if err != nil {
+		return err
+	}
+	for _, symbol := range symbols {
+		if _, ok := cursors[symbol]; !ok {
+			cursors[symbol] = models.SyncState{Symbol: symbol}
+		}
+	}
+
+	syncStartedAt := time.Now()
+	history, err := fetcher.FetchOrderHistory(ctx, account.ID, cursors)
+	var symbolErrs exchanges.SymbolErrors
+	if err != nil && !errors.As(err, &symbolErrs) {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	err = s.syncStateRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.orderHistoryRepository.SaveOrderHistory(txCtx, history); err != nil {
+			return err
+		}
+		if err := s.syncStateRepository.SaveCursors(txCtx, orderHistoryCursors(account, syncMarket, history)); err != nil {
+			return err
+		}
+		if len(symbolErrs) > 0 {
+			// Order của symbol thành công vẫn được lưu, lần đồng bộ được ghi nhận là thất bại
+			// để symbol lỗi hiện trong trạng thái đồng bộ
+			return nil
+		}
+		return s.syncStateRepository.MarkSuccess(txCtx, account.ID, account.Exchange, syncMarket, syncStartedAt)
+	})
+	if err != nil {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	if len(symbolErrs) > 0 {
+		s.markFailure(ctx, account, symbolErrs)
+		return symbolErrs
+	}
+	return nil
+}
+
+// orderHistoryCursors tính cursor mới cho từng symbol: order ID lớn nhất đã lấy, hoặc ngay trước
+// order chưa kết thúc nhỏ nhất để order đó được lấy lại ở lần đồng bộ sau
+func orderHistoryCursors(account models.RegisteredAccount, syncMarket string, history []models.OrderHistory) []models.SyncState {
+	type symbolCursor struct {
+		lastID     int64
+		minOpenID  int64
+		lastTime   time.Time
+		hasOpenIDs bool
+	}
+	bySymbol := make(map[string]*symbolCursor)
+	var symbols []string
+	for _, order := range history {
+		cursor, ok := bySymbol[order.Symbol]
+		if !ok {
+			cursor = &symbolCursor{}
+			bySymbol[order.Symbol] = cursor
+			symbols = append(symbols, order.Symbol)
+		}
+		if !order.IsFinal() && (!cursor.hasOpenIDs || order.OrderID < cursor.minOpenID) {
+			cursor.minOpenID = order.OrderID
+			cursor.hasOpenIDs = true
+		}
+		if order.OrderID > cursor.lastID {
+			cursor.lastID = order.OrderID
+		}
+		if order.Time.After(cursor.lastTime) {
+			cursor.lastTime = order.Time
+		}
+	}
+	states := make([]models.SyncState, 0, len(symbols))
+	for _, symbol := range symbols {
+		cursor := bySymbol[symbol]
+		lastID := cursor.lastID
+		if cursor.hasOpenIDs {
+			lastID = cursor.minOpenID - 1
+		}
+		states = append(states, models.SyncState{
+			RegisteredAccountID: account.ID,
+			Exchange:            account.Exchange,
+			Market:              syncMarket,
+			Symbol:              symbol,
+			LastTradeID:         lastID,
+			LastTradeTime:       cursor.lastTime,
+		})
+	}
+	return states
+}
+
+func (s *OrderHistoryService) markFailure(ctx context.Context, account models.RegisteredAccount, syncErr error) {
+	err := s.syncStateRepository.MarkFailure(ctx, account.ID, account.Exchange, OrderHistorySyncMarket(account.Market), syncErr)
+	if err != nil {
+		log.Println("Mark order history sync failure error for account", account.Username, ":", err)
+	}
+}
+
+// GetOrderHistory lấy một trang order kèm fill của các tài khoản
+func (s *OrderHistoryService) GetOrderHistory(ctx context.Context, accountIDs []primitive.ObjectID, filter repositories.OrderHistoryFilter) ([]models.OrderHistoryWithFills, error) {
+	return s.orderHistoryRepository.GetOrderHistory(ctx, accountIDs, filter)
+}
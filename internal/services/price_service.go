@@ -0,0 +1,116 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Asset trung gian khi không có cặp trực tiếp với quote asset
+const bridgeAsset = "BTC"
+
+// PriceFeed cung cấp giá đóng cửa lịch sử của một symbol
+type PriceFeed interface {
+	CloseAt(ctx context.Context, symbol string, at time.Time) (decimal.Decimal, error)
+}
+
+// PriceService quy đổi asset sang quote asset (asset thanh toán) theo giá kline tại thời điểm giao dịch,
+// giá được cache trong bộ nhớ và trong Mongo theo từng phút
+type PriceService struct {
+	feed            PriceFeed
+	priceRepository *repositories.PriceRepository
+	priceCache      *cache.Cache
+	quoteAsset      string
+}
+
+func NewPriceService(feed PriceFeed, priceRepository *repositories.PriceRepository, quoteAsset string) *PriceService {
+	return &PriceService{
+		feed:            feed,
+		priceRepository: priceRepository,
+		priceCache:      cache.New(6*time.Hour, 1*time.Hour),
+		quoteAsset:      quoteAsset,
+	}
+}
+
+// ConvertAt quy đổi amount của asset sang quote asset theo giá tại thời điểm at
+func (s *PriceService) ConvertAt(ctx context.Context, asset string, amount decimal.Decimal, at time.Time) (decimal.Decimal, error) {
+	if asset == s.quoteAsset {
+		return amount, nil
+	}
+	price, err := s.PriceAt(ctx, asset, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(price), nil
+}
+
+// PriceAt trả về giá của asset theo quote asset tại phút chứa thời điểm at
+func (s *PriceService) PriceAt(ctx context.Context, asset string, at time.Time) (decimal.Decimal, error) {
+	if asset == s.quoteAsset {
+		return decimal.NewFromInt(1), nil
+	}
+	minute := at.UTC().Truncate(time.Minute)
+	cacheKey := fmt.Sprintf("%s:%s:%d", asset, s.quoteAsset, minute.Unix())
+	if cached, found := s.priceCache.Get(cacheKey); found {
+		return cached.(decimal.Decimal), nil
+	}
+
+	stored, err := s.priceRepository.GetPrice(ctx, asset, s.quoteAsset, minute)
+	if err == nil {
+		if price, err := decimal.NewFromString(stored.Price); err == nil {
+			s.priceCache.Set(cacheKey, price, cache.DefaultExpiration)
+			return price, nil
+		}
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		log.Printf("Get cached price error for %s at %s: %v", asset, minute, err)
+	}
+
+	price, source, err := s.fetchPrice(ctx, asset, minute)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	err = s.priceRepository.SavePrice(ctx, models.Price{
+		Asset:  asset,
+		Quote:  s.quoteAsset,
+		Time:   minute,
+		Price:  price.String(),
+		Source: source,
+	})
+	if err != nil {
+		log.Printf("Save price error for %s at %s: %v", asset, minute, err)
+	}
+	s.priceCache.Set(cacheKey, price, cache.DefaultExpiration)
+	return price, nil
+}
+
+// fetchPrice thử lần lượt cặp trực tiếp, cặp ngược và qua asset trung gian
+func (s *PriceService) fetchPrice(ctx context.Context, asset string, minute time.Time) (decimal.Decimal, string, error) {
+	direct := asset + s.quoteAsset
+	if price, err := s.feed.CloseAt(ctx, direct, minute); err == nil {
+		return price, direct, nil
+	}
+	inverse := s.quoteAsset + asset
+	if price, err := s.feed.CloseAt(ctx, inverse, minute); err == nil && !price.IsZero() {
+		return decimal.NewFromInt(1).Div(price), "1/" + inverse, nil
+	}
+	if asset != bridgeAsset {
+		toBridge := asset + bridgeAsset
+		bridgeToQuote := bridgeAsset + s.quoteAsset
+		first, err := s.feed.CloseAt(ctx, toBridge, minute)
+		if err == nil {
+			second, err := s.feed.CloseAt(ctx, bridgeToQuote, minute)
+			if err == nil {
+				return first.Mul(second), toBridge + "*" + bridgeToQuote, nil
+			}
+		}
+	}
+	return decimal.Zero, "", fmt.Errorf("no price for %s/%s at %s", asset, s.quoteAsset, minute.Format(time.RFC3339))
+}
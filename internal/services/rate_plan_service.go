@@ -0,0 +1,191 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidRatePlan là lỗi dữ liệu gói hoàn phí không hợp lệ
+var ErrInvalidRatePlan = errors.New("invalid rate plan")
+
+type RatePlanService struct {
+	ratePlanRepository          *repositories.RatePlanRepository
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+}
+
+func NewRatePlanService(ratePlanRepository *repositories.RatePlanRepository, registeredAccountRepository *repositories.RegisteredAccountRepository) *RatePlanService {
+	return &RatePlanService{
+		ratePlanRepository:          ratePlanRepository,
+		registeredAccountRepository: registeredAccountRepository,
+	}
+}
+
+// validateRatePlan kiểm tra và sắp xếp các bậc theo MinVolume tăng dần
+func validateRatePlan(plan *models.RatePlan) error {
+	if plan.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRatePlan)
+	}
+	if len(plan.Tiers) == 0 {
+		return fmt.Errorf("%w: at least one tier is required", ErrInvalidRatePlan)
+	}
+	one := decimal.NewFromInt(1)
+	for _, tier := range plan.Tiers {
+		minVolume, err := decimal.NewFromString(tier.MinVolume)
+		if err != nil || minVolume.IsNegative() {
+			return fmt.Errorf("%w: tier %q has invalid minVolume", ErrInvalidRatePlan, tier.Name)
+		}
+		for market, rateStr := range tier.Rates {
+			rate, err := decimal.NewFromString(rateStr)
+			if err != nil || rate.IsNegative() || rate.GreaterThan(one) {
+				return fmt.Errorf("%w: tier %q has invalid rate for market %s", ErrInvalidRatePlan, tier.Name, market)
+			}
+		}
+	}
+	sort.SliceStable(plan.Tiers, func(i, j int) bool {
+		return decimal.RequireFromString(plan.Tiers[i].MinVolume).LessThan(decimal.RequireFromString(plan.Tiers[j].MinVolume))
+	})
+	return nil
+}
+
+func (s *RatePlanService) CreatePlan(ctx context.Context, plan models.RatePlan) (models.RatePlan, error) {
+	if err := validateRatePlan(&plan); err != nil {
+		return plan, err
+	}
+	now := time.Now()
+	plan.ID = primitive.NewObjectID()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+	err := s.ratePlanRepository.SaveRatePlan(ctx, plan)
+	return plan, err
+}
+
+func (s *RatePlanService) UpdatePlan(ctx context.Context, plan models.RatePlan) (models.RatePlan, error) {
+	if err := validateRatePlan(&plan); err != nil {
+		return plan, err
+	}
+	existing, err := s.ratePlanRepository.GetRatePlan(ctx, plan.ID)
+	if err != nil {
+		return plan, err
+	}
+	plan.CreatedAt = existing.CreatedAt
+	plan.UpdatedAt = time.Now()
+	err = s.ratePlanRepository.UpdateRatePlan(ctx, plan)
+	return plan, err
+}
+
+func (s *RatePlanService) DeletePlan(ctx context.Context, id primitive.ObjectID) error {
+	return s.ratePlanRepository.DeleteRatePlan(ctx, id)
+}
+
+func (s *RatePlanService) GetPlan(ctx context.Context, id primitive.ObjectID) (models.RatePlan, error) {
+	return s.ratePlanRepository.GetRatePlan(ctx, id)
+}
+
+func (s *RatePlanService) ListPlans(ctx context.Context) ([]models.RatePlan, error) {
+	return s.ratePlanRepository.GetAllRatePlans(ctx)
+}
+
+// AssignPlan gán gói hoàn phí cho account, áp dụng cho các trade từ effectiveFrom
+func (s *RatePlanService) AssignPlan(ctx context.Context, accountID, planID primitive.ObjectID, effectiveFrom time.Time) error {
+	if _, err := s.ratePlanRepository.GetRatePlan(ctx, planID); err != nil {
+		return err
+	}
+	return s.registeredAccountRepository.AssignRatePlan(ctx, accountID, models.RatePlanAssignment{
+		RatePlanID:    planID,
+		EffectiveFrom: effectiveFrom,
+	})
+}
+
+// AppliedRate là tỉ lệ hoàn phí được chọn cho một trade
+type AppliedRate struct {
+	RatePlanID   primitive.ObjectID
+	RatePlanName string
+	Tier         string
+	Rate         decimal.Decimal
+}
+
+// RateResolver chọn tỉ lệ hoàn phí cho từng trade của một account trong một kỳ
+type RateResolver struct {
+	account      models.RegisteredAccount
+	plans        map[primitive.ObjectID]models.RatePlan
+	referrerPlan *models.RatePlan
+	volume       decimal.Decimal
+	defaultRate  decimal.Decimal
+}
+
+// NewRateResolver nạp các gói hoàn phí liên quan tới account. periodVolume là khối lượng
+// giao dịch của account trong kỳ, dùng để chọn bậc hoàn phí.
+func (s *RatePlanService) NewRateResolver(ctx context.Context, account models.RegisteredAccount, periodVolume, defaultRate decimal.Decimal) (*RateResolver, error) {
+	plans, err := s.ratePlanRepository.GetAllRatePlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRateResolver(account, plans, periodVolume, defaultRate), nil
+}
+
+func newRateResolver(account models.RegisteredAccount, plans []models.RatePlan, periodVolume, defaultRate decimal.Decimal) *RateResolver {
+	resolver := &RateResolver{
+		account:     account,
+		plans:       make(map[primitive.ObjectID]models.RatePlan, len(plans)),
+		volume:      periodVolume,
+		defaultRate: defaultRate,
+	}
+	for i, plan := range plans {
+		resolver.plans[plan.ID] = plan
+		if account.ReferrerID == "" || plan.ReferrerID != account.ReferrerID {
+			continue
+		}
+		if plan.Exchange != "" && plan.Exchange != account.Exchange {
+			continue
+		}
+		// Ưu tiên gói dành riêng cho exchange của account
+		if resolver.referrerPlan == nil || (resolver.referrerPlan.Exchange == "" && plan.Exchange != "") {
+			resolver.referrerPlan = &plans[i]
+		}
+	}
+	return resolver
+}
+
+// RateAt trả về tỉ lệ hoàn phí cho trade tại thời điểm t: gói được gán có hiệu lực tại t,
+// nếu không có thì dùng gói của partner giới thiệu, cuối cùng là tỉ lệ mặc định
+func (r *RateResolver) RateAt(t time.Time) AppliedRate {
+	var plan *models.RatePlan
+	for i := len(r.account.RatePlans) - 1; i >= 0; i-- {
+		assignment := r.account.RatePlans[i]
+		if assignment.EffectiveFrom.After(t) {
+			continue
+		}
+		if p, ok := r.plans[assignment.RatePlanID]; ok {
+			plan = &p
+		}
+		break
+	}
+	if plan == nil {
+		plan = r.referrerPlan
+	}
+	if plan == nil {
+		return AppliedRate{Rate: r.defaultRate}
+	}
+	applied := AppliedRate{RatePlanID: plan.ID, RatePlanName: plan.Name, Rate: decimal.Zero}
+	// Các bậc đã được sắp xếp theo MinVolume tăng dần, chọn bậc cao nhất đạt được
+	for _, tier := range plan.Tiers {
+		minVolume, err := decimal.NewFromString(tier.MinVolume)
+		if err != nil || r.volume.LessThan(minVolume) {
+			break
+		}
+		applied.Tier = tier.Name
+		applied.Rate = decimal.Zero
+		if rate, err := decimal.NewFromString(tier.Rates[r.account.Market]); err == nil {
+			applied.Rate = rate
+		}
+	}
+	return applied
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testRatePlan(name string, tiers ...models.RateTier) models.RatePlan {
+	return models.RatePlan{ID: primitive.NewObjectID(), Name: name, Tiers: tiers}
+}
+
+func newTestResolver(account models.RegisteredAccount, volume string, plans ...models.RatePlan) *RateResolver {
+	return newRateResolver(account, plans, decimal.RequireFromString(volume), decimal.RequireFromString("0.2"))
+}
+
+func TestRateAtSelectsTierByVolume(t *testing.T) {
+	plan := testRatePlan("vip",
+		models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "0.1", "futures": "0.15"}},
+		models.RateTier{Name: "silver", MinVolume: "100000", Rates: map[string]string{"spot": "0.2"}},
+		models.RateTier{Name: "gold", MinVolume: "1000000", Rates: map[string]string{"spot": "0.3", "futures": "0.35"}},
+	)
+	effectiveFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		market string
+		volume string
+		tier   string
+		rate   string
+	}{
+		{name: "below second tier", market: "spot", volume: "99999.99", tier: "base", rate: "0.1"},
+		{name: "exactly second tier", market: "spot", volume: "100000", tier: "silver", rate: "0.2"},
+		{name: "top tier", market: "spot", volume: "5000000", tier: "gold", rate: "0.3"},
+		{name: "market missing in tier", market: "futures", volume: "200000", tier: "silver", rate: "0"},
+		{name: "futures top tier", market: "futures", volume: "1000000", tier: "gold", rate: "0.35"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := models.RegisteredAccount{
+				Market:    tt.market,
+				RatePlans: []models.RatePlanAssignment{{RatePlanID: plan.ID, EffectiveFrom: effectiveFrom}},
+			}
+			applied := newTestResolver(account, tt.volume, plan).RateAt(effectiveFrom.Add(time.Hour))
+			if applied.Tier != tt.tier {
+				t.Errorf("tier = %q, want %q", applied.Tier, tt.tier)
+			}
+			if !applied.Rate.Equal(decimal.RequireFromString(tt.rate)) {
+				t.Errorf("rate = %s, want %s", applied.Rate, tt.rate)
+			}
+		})
+	}
+}
+
+func TestRateAtUsesAssignmentInEffect(t *testing.T) {
+	oldPlan := testRatePlan("old", models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "0.1"}})
+	newPlan := testRatePlan("new", models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "0.4"}})
+	referrerPlan := testRatePlan("partner", models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "0.25"}})
+	referrerPlan.ReferrerID = "partner-1"
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	account := models.RegisteredAccount{
+		Market:     "spot",
+		ReferrerID: "partner-1",
+		RatePlans: []models.RatePlanAssignment{
+			{RatePlanID: oldPlan.ID, EffectiveFrom: first},
+			{RatePlanID: newPlan.ID, EffectiveFrom: second},
+		},
+	}
+	resolver := newTestResolver(account, "0", oldPlan, newPlan, referrerPlan)
+	tests := []struct {
+		name string
+		at   time.Time
+		plan string
+		rate string
+	}{
+		{name: "before any assignment uses referrer plan", at: first.Add(-time.Millisecond), plan: "partner", rate: "0.25"},
+		{name: "on effective_from of first plan", at: first, plan: "old", rate: "0.1"},
+		{name: "just before second plan", at: second.Add(-time.Millisecond), plan: "old", rate: "0.1"},
+		{name: "on effective_from of second plan", at: second, plan: "new", rate: "0.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := resolver.RateAt(tt.at)
+			if applied.RatePlanName != tt.plan {
+				t.Errorf("plan = %q, want %q", applied.RatePlanName, tt.plan)
+			}
+			if !applied.Rate.Equal(decimal.RequireFromString(tt.rate)) {
+				t.Errorf("rate = %s, want %s", applied.Rate, tt.rate)
+			}
+		})
+	}
+}
+
+func TestRateAtFallsBackToDefaultRate(t *testing.T) {
+	applied := newTestResolver(models.RegisteredAccount{Market: "spot"}, "0").RateAt(time.Now())
+	if applied.RatePlanName != "" || !applied.Rate.Equal(decimal.RequireFromString("0.2")) {
+		t.Errorf("applied = %+v, want default rate 0.2 without plan", applied)
+	}
+}
+
+func TestValidateRatePlan(t *testing.T) {
+	tests := []struct {
+		name  string
+		plan  models.RatePlan
+		valid bool
+	}{
+		{name: "valid", plan: testRatePlan("p", models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "0.2"}}), valid: true},
+		{name: "missing name", plan: testRatePlan("", models.RateTier{Name: "base", MinVolume: "0"})},
+		{name: "no tiers", plan: testRatePlan("p")},
+		{name: "negative min volume", plan: testRatePlan("p", models.RateTier{Name: "base", MinVolume: "-1"})},
+		{name: "rate above one", plan: testRatePlan("p", models.RateTier{Name: "base", MinVolume: "0", Rates: map[string]string{"spot": "1.5"}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRatePlan(&tt.plan)
+			if tt.valid && err != nil {
+				t.Errorf("validateRatePlan: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidRatePlan) {
+				t.Errorf("validateRatePlan error = %v, want ErrInvalidRatePlan", err)
+			}
+		})
+	}
+}
+
+func TestValidateRatePlanSortsTiers(t *testing.T) {
+	plan := testRatePlan("p",
+		models.RateTier{Name: "gold", MinVolume: "1000000"},
+		models.RateTier{Name: "base", MinVolume: "0"},
+		models.RateTier{Name: "silver", MinVolume: "100000"},
+	)
+	if err := validateRatePlan(&plan); err != nil {
+		t.Fatalf("validateRatePlan: %v", err)
+	}
+	for i, want := range []string{"base", "silver", "gold"} {
+		if plan.Tiers[i].Name != want {
+			t.Errorf("tier %d = %q, want %q", i, plan.Tiers[i].Name, want)
+		}
+	}
+}
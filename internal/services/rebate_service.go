@@ -0,0 +1,338 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultRebateRate      = "0.2"
+	defaultSettlementAsset = "USDT"
+)
+
+// ErrStatementOverlap là lỗi kỳ tính hoàn phí giao với kỳ của statement đã có, tránh trả hoàn phí hai lần
+var ErrStatementOverlap = errors.New("rebate period overlaps an existing statement")
+
+// AssetConverter quy đổi một lượng asset sang asset thanh toán tại thời điểm cho trước
+type AssetConverter interface {
+	ConvertAt(ctx context.Context, asset string, amount decimal.Decimal, at time.Time) (decimal.Decimal, error)
+}
+
+// SettlementAsset đọc asset thanh toán hoàn phí từ env, mặc định USDT
+func SettlementAsset() string {
+	asset := strings.ToUpper(os.Getenv("REBATE_SETTLEMENT_ASSET"))
+	if asset == "" {
+		return defaultSettlementAsset
+	}
+	return asset
+}
+
+type RebateService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	orderRepository             *repositories.OrderRepository
+	statementRepository         *repositories.RebateStatementRepository
+	ratePlanService             *RatePlanService
+	converter                   AssetConverter
+	settlementAsset             string
+	rebateRate                  decimal.Decimal
+}
+
+func NewRebateService(registeredAccountRepository *repositories.RegisteredAccountRepository, orderRepository *repositories.OrderRepository, statementRepository *repositories.RebateStatementRepository, ratePlanService *RatePlanService, converter AssetConverter) *RebateService {
+	// Tỉ lệ hoàn phí mặc định cho account chưa có gói hoàn phí, mặc định 20%
+	rate, err := decimal.NewFromString(os.Getenv("REBATE_RATE"))
+	if err != nil || rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(1)) {
+		rate = decimal.RequireFromString(defaultRebateRate)
+	}
+	return &RebateService{
+		registeredAccountRepository: registeredAccountRepository,
+		orderRepository:             orderRepository,
+		statementRepository:         statementRepository,
+		ratePlanService:             ratePlanService,
+		converter:                   converter,
+		settlementAsset:             SettlementAsset(),
+		rebateRate:                  rate,
+	}
+}
+
+// CalculateAllStatements tính statement cho tất cả account trong kỳ [start, end)
+func (s *RebateService) CalculateAllStatements(ctx context.Context, start, end time.Time) error {
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if _, err := s.CalculateStatement(ctx, account, start, end); err != nil {
+			log.Printf("Calculate rebate statement error for account %s: %v", account.Username, err)
+		}
+	}
+	return nil
+}
+
+// CalculateStatement cộng phí giao dịch của account trong kỳ [start, end), quy đổi từng trade
+// sang asset thanh toán tại thời điểm khớp lệnh và áp dụng tỉ lệ hoàn phí có hiệu lực tại ngày giao dịch.
+// Tính lại đúng kỳ đã có thì ghi đè, kỳ giao với statement khác trả về ErrStatementOverlap.
+func (s *RebateService) CalculateStatement(ctx context.Context, account models.RegisteredAccount, start, end time.Time) (*models.RebateStatement, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid period: %s - %s", start, end)
+	}
+	overlaps, err := s.statementRepository.HasOverlappingStatement(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, ErrStatementOverlap
+	}
+	orders, err := s.tradesInRange(ctx, account, start, end)
+	if err != nil {
+		return nil, err
+	}
+	volume := s.periodVolume(ctx, orders)
+	resolver, err := s.ratePlanService.NewRateResolver(ctx, account, volume, s.rebateRate)
+	if err != nil {
+		return nil, err
+	}
+
+	type rateTotal struct {
+		applied    AppliedRate
+		commission decimal.Decimal
+		rebate     decimal.Decimal
+		tradeCount int
+	}
+	fees := newCommissionAccumulator()
+	rateTotals := make(map[string]*rateTotal)
+	var rateKeys []string
+	totalRebate := decimal.Zero
+	for _, order := range orders {
+		converted, ok := s.addCommission(ctx, account, fees, order)
+		if !ok {
+			continue
+		}
+
+		applied := resolver.RateAt(order.Time)
+		rateKey := fmt.Sprintf("%s:%s:%s", applied.RatePlanID.Hex(), applied.Tier, applied.Rate.String())
+		byRate, ok := rateTotals[rateKey]
+		if !ok {
+			byRate = &rateTotal{applied: applied}
+			rateTotals[rateKey] = byRate
+			rateKeys = append(rateKeys, rateKey)
+		}
+		rebate := converted.Mul(applied.Rate)
+		byRate.commission = byRate.commission.Add(converted)
+		byRate.rebate = byRate.rebate.Add(rebate)
+		byRate.tradeCount++
+		totalRebate = totalRebate.Add(rebate)
+	}
+
+	rates := make([]models.RateBreakdown, 0, len(rateKeys))
+	for _, key := range rateKeys {
+		byRate := rateTotals[key]
+		rates = append(rates, models.RateBreakdown{
+			RatePlanID:      byRate.applied.RatePlanID,
+			RatePlanName:    byRate.applied.RatePlanName,
+			Tier:            byRate.applied.Tier,
+			Rate:            byRate.applied.Rate.String(),
+			TradeCount:      byRate.tradeCount,
+			TotalCommission: byRate.commission.String(),
+			RebateAmount:    byRate.rebate.String(),
+		})
+	}
+	effectiveRate := decimal.Zero
+	if !fees.total.IsZero() {
+		effectiveRate = totalRebate.Div(fees.total)
+	}
+
+	statement := models.RebateStatement{
+		ID:                  primitive.NewObjectID(),
+		RegisteredAccountID: account.ID,
+		Exchange:            account.Exchange,
+		Market:              account.Market,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		SettlementAsset:     s.settlementAsset,
+		RebateRate:          effectiveRate.String(),
+		Volume:              volume.String(),
+		Commissions:         fees.totals(),
+		Rates:               rates,
+		TotalCommission:     fees.total.String(),
+		RebateAmount:        totalRebate.String(),
+		TradeCount:          len(orders),
+		CreatedAt:           time.Now(),
+	}
+	saved, err := s.statementRepository.SaveStatement(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// SummarizeFees tổng hợp phí giao dịch của account trong kỳ [start, end) theo từng asset,
+// kèm giá trị quy đổi sang asset thanh toán
+func (s *RebateService) SummarizeFees(ctx context.Context, account models.RegisteredAccount, start, end time.Time) (*models.FeeSummary, error) {
+	orders, err := s.tradesInRange(ctx, account, start, end)
+	if err != nil {
+		return nil, err
+	}
+	fees := newCommissionAccumulator()
+	for _, order := range orders {
+		s.addCommission(ctx, account, fees, order)
+	}
+	return &models.FeeSummary{
+		RegisteredAccountID: account.ID,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		SettlementAsset:     s.settlementAsset,
+		Commissions:         fees.totals(),
+		TotalCommission:     fees.total.String(),
+		TradeCount:          len(orders),
+	}, nil
+}
+
+// tradesInRange lấy trade của account trong kỳ [start, end), bỏ qua lãi vay margin
+// vì lãi vay không được hoàn phí
+func (s *RebateService) tradesInRange(ctx context.Context, account models.RegisteredAccount, start, end time.Time) ([]models.Order, error) {
+	orders, err := s.orderRepository.GetOrdersInRange(ctx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	trades := orders[:0]
+	for _, order := range orders {
+		if order.IsTrade() {
+			trades = append(trades, order)
+		}
+	}
+	return trades, nil
+}
+
+// addCommission cộng phí của một trade vào accumulator, trả về giá trị đã quy đổi
+// và false nếu trade không có phí hoặc không quy đổi được
+func (s *RebateService) addCommission(ctx context.Context, account models.RegisteredAccount, fees *commissionAccumulator, order models.Order) (decimal.Decimal, bool) {
+	commission := order.Commission.Decimal
+	if commission.IsZero() {
+		return decimal.Zero, false
+	}
+	converted, err := s.converter.ConvertAt(ctx, order.CommissionAsset, commission, order.Time)
+	if err != nil {
+		log.Printf("Convert commission error for account %s, trade %s: %v", account.Username, order.ID, err)
+		fees.add(order.CommissionAsset, commission, decimal.Zero, false)
+		return decimal.Zero, false
+	}
+	fees.add(order.CommissionAsset, commission, converted, true)
+	return converted, true
+}
+
+type assetTotal struct {
+	amount           decimal.Decimal
+	settlementAmount decimal.Decimal
+	tradeCount       int
+	unconverted      int
+}
+
+// commissionAccumulator cộng dồn phí theo commission asset
+type commissionAccumulator struct {
+	byAsset map[string]*assetTotal
+	total   decimal.Decimal
+}
+
+func newCommissionAccumulator() *commissionAccumulator {
+	return &commissionAccumulator{byAsset: make(map[string]*assetTotal)}
+}
+
+func (a *commissionAccumulator) add(asset string, amount, converted decimal.Decimal, ok bool) {
+	total, exists := a.byAsset[asset]
+	if !exists {
+		total = &assetTotal{}
+		a.byAsset[asset] = total
+	}
+	total.amount = total.amount.Add(amount)
+	total.tradeCount++
+	if !ok {
+		total.unconverted++
+		return
+	}
+	total.settlementAmount = total.settlementAmount.Add(converted)
+	a.total = a.total.Add(converted)
+}
+
+func (a *commissionAccumulator) totals() []models.CommissionTotal {
+	assets := make([]string, 0, len(a.byAsset))
+	for asset := range a.byAsset {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	commissions := make([]models.CommissionTotal, 0, len(assets))
+	for _, asset := range assets {
+		total := a.byAsset[asset]
+		commissions = append(commissions, models.CommissionTotal{
+			Asset:            asset,
+			Amount:           total.amount.String(),
+			SettlementAmount: total.settlementAmount.String(),
+			TradeCount:       total.tradeCount,
+			Unconverted:      total.unconverted,
+		})
+	}
+	return commissions
+}
+
+// periodVolume tính tổng giá trị giao dịch trong kỳ quy đổi sang asset thanh toán,
+// trade không xác định được quote asset hoặc không quy đổi được thì bỏ qua
+func (s *RebateService) periodVolume(ctx context.Context, orders []models.Order) decimal.Decimal {
+	volume := decimal.Zero
+	for _, order := range orders {
+		quoteQuantity := order.QuoteQuantity.Decimal
+		if quoteQuantity.IsZero() {
+			continue
+		}
+		quoteAsset := QuoteAssetOf(order.Symbol)
+		if quoteAsset == "" {
+			continue
+		}
+		// Hợp đồng COIN-M (và inverse swap của OKX) lưu giá trị danh nghĩa theo USD,
+		// không có cặp giá USD nên quy đổi như USDT
+		if quoteAsset == "USD" {
+			quoteAsset = "USDT"
+		}
+		converted, err := s.converter.ConvertAt(ctx, quoteAsset, quoteQuantity, order.Time)
+		if err != nil {
+			continue
+		}
+		volume = volume.Add(converted)
+	}
+	return volume
+}
+
+// Các quote asset phổ biến, asset dài hơn đặt trước để khớp đúng hậu tố (FDUSD trước USD)
+var knownQuoteAssets = []string{"FDUSD", "USDT", "USDC", "TUSD", "BUSD", "BTC", "ETH", "BNB", "TRY", "EUR", "BRL", "USD"}
+
+// QuoteAssetOf đoán quote asset từ tên symbol, trả về rỗng nếu không nhận ra. Symbol COIN-M có
+// hậu tố hợp đồng (BTCUSD_PERP, BTCUSD_250627) được bỏ hậu tố, instId của OKX (BTC-USDT,
+// BTC-USD-SWAP) lấy phần thứ hai.
+func QuoteAssetOf(symbol string) string {
+	if pair, _, ok := strings.Cut(symbol, "_"); ok {
+		symbol = pair
+	}
+	if parts := strings.Split(symbol, "-"); len(parts) >= 2 {
+		return parts[1]
+	}
+	for _, quote := range knownQuoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return quote
+		}
+	}
+	return ""
+}
+
+// GetStatements lấy các statement đã tính của account
+func (s *RebateService) GetStatements(ctx context.Context, accountID primitive.ObjectID) ([]models.RebateStatement, error) {
+	return s.statementRepository.GetStatementsByAccountID(ctx, accountID)
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeConverter quy đổi theo giá cố định của từng asset, asset không có giá trả về lỗi
+type fakeConverter map[string]string
+
+func (f fakeConverter) ConvertAt(ctx context.Context, asset string, amount decimal.Decimal, at time.Time) (decimal.Decimal, error) {
+	price, ok := f[asset]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no price for %s", asset)
+	}
+	return amount.Mul(decimal.RequireFromString(price)), nil
+}
+
+func TestQuoteAssetOf(t *testing.T) {
+	tests := []struct {
+		symbol string
+		quote  string
+	}{
+		{symbol: "BTCUSDT", quote: "USDT"},
+		{symbol: "ETHBTC", quote: "BTC"},
+		{symbol: "BTCFDUSD", quote: "FDUSD"},
+		{symbol: "BTCUSDC", quote: "USDC"},
+		{symbol: "BTCUSD_PERP", quote: "USD"},
+		{symbol: "ETHUSD_250627", quote: "USD"},
+		{symbol: "BTC-USDT", quote: "USDT"},
+		{symbol: "BTC-USDT-SWAP", quote: "USDT"},
+		{symbol: "BTC-USD-SWAP", quote: "USD"},
+		{symbol: "USDT", quote: ""},
+		{symbol: "ABCXYZ", quote: ""},
+	}
+	for _, tt := range tests {
+		if got := QuoteAssetOf(tt.symbol); got != tt.quote {
+			t.Errorf("QuoteAssetOf(%q) = %q, want %q", tt.symbol, got, tt.quote)
+		}
+	}
+}
+
+func TestAddCommissionAccumulatesByAsset(t *testing.T) {
+	service := &RebateService{converter: fakeConverter{"USDT": "1", "BNB": "600"}}
+	account := models.RegisteredAccount{Username: "test"}
+	orders := []models.Order{
+		{ID: "1", Commission: models.DecimalFromString("1.5"), CommissionAsset: "USDT"},
+		{ID: "2", Commission: models.DecimalFromString("0.01"), CommissionAsset: "BNB"},
+		{ID: "3", Commission: models.DecimalFromString("0.5"), CommissionAsset: "USDT"},
+		{ID: "4", Commission: models.DecimalFromString("2"), CommissionAsset: "XYZ"},
+		{ID: "5", Commission: models.DecimalFromString("0"), CommissionAsset: "USDT"},
+	}
+	fees := newCommissionAccumulator()
+	for _, order := range orders {
+		service.addCommission(context.Background(), account, fees, order)
+	}
+	if !fees.total.Equal(decimal.RequireFromString("8")) {
+		t.Errorf("total = %s, want 8", fees.total)
+	}
+	want := []models.CommissionTotal{
+		{Asset: "BNB", Amount: "0.01", SettlementAmount: "6", TradeCount: 1},
+		{Asset: "USDT", Amount: "2", SettlementAmount: "2", TradeCount: 2},
+		{Asset: "XYZ", Amount: "2", SettlementAmount: "0", TradeCount: 1, Unconverted: 1},
+	}
+	got := fees.totals()
+	if len(got) != len(want) {
+		t.Fatalf("got %d commission totals, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("commission total %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPeriodVolume(t *testing.T) {
+	service := &RebateService{converter: fakeConverter{"USDT": "1", "BTC": "60000"}}
+	tests := []struct {
+		name   string
+		orders []models.Order
+		volume string
+	}{
+		{
+			name:   "usdt quote",
+			orders: []models.Order{{Symbol: "BTCUSDT", QuoteQuantity: models.DecimalFromString("1000")}},
+			volume: "1000",
+		},
+		{
+			name:   "btc quote converted",
+			orders: []models.Order{{Symbol: "ETHBTC", QuoteQuantity: models.DecimalFromString("0.5")}},
+			volume: "30000",
+		},
+		{
+			name: "coin-m and okx inverse usd notional counted as usdt",
+			orders: []models.Order{
+				{Symbol: "BTCUSD_PERP", QuoteQuantity: models.DecimalFromString("500")},
+				{Symbol: "BTC-USD-SWAP", QuoteQuantity: models.DecimalFromString("200")},
+			},
+			volume: "700",
+		},
+		{
+			name: "unknown quote and unconvertible asset skipped",
+			orders: []models.Order{
+				{Symbol: "ABCXYZ", QuoteQuantity: models.DecimalFromString("100")},
+				{Symbol: "BTCEUR", QuoteQuantity: models.DecimalFromString("100")},
+				{Symbol: "BTCUSDT", QuoteQuantity: models.DecimalFromString("10")},
+			},
+			volume: "10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := service.periodVolume(context.Background(), tt.orders); !got.Equal(decimal.RequireFromString(tt.volume)) {
+				t.Errorf("volume = %s, want %s", got, tt.volume)
+			}
+		})
+	}
+}
@@ -5,21 +5,30 @@ import (
 	"autobackcom/internal/models"
 	"autobackcom/internal/repositories"
 	"context"
+	"errors"
 	"log"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// Số trade tối đa ghi trong một transaction, lần đồng bộ đầu tiên có thể lấy về hàng chục nghìn
+// trade nên không ghi tất cả trong một transaction (MongoDB giới hạn transaction 60 giây)
+const tradeSaveBatchSize = 1000
+
 type TradeHistoryService struct {
 	registeredAccountRepository *repositories.RegisteredAccountRepository
 	orderRepository             *repositories.OrderRepository
+	syncStateRepository         *repositories.SyncStateRepository
 	clientManager               *ClientManagerService
 }
 
-func NewTradeHistoryService(registeredAccountRepository *repositories.RegisteredAccountRepository, orderRepository *repositories.OrderRepository, clientManager *ClientManagerService) *TradeHistoryService {
+func NewTradeHistoryService(registeredAccountRepository *repositories.RegisteredAccountRepository, orderRepository *repositories.OrderRepository, syncStateRepository *repositories.SyncStateRepository, clientManager *ClientManagerService) *TradeHistoryService {
 	return &TradeHistoryService{
 		registeredAccountRepository: registeredAccountRepository,
 		orderRepository:             orderRepository,
+		syncStateRepository:         syncStateRepository,
 		clientManager:               clientManager,
 	}
 }
@@ -37,6 +46,10 @@ func (s *TradeHistoryService) FetchAllAccountTradeHistory(ctx context.Context) e
 	var accountWg sync.WaitGroup
 
 	for _, account := range accounts {
+		// Bỏ qua tài khoản bị tạm dừng hoặc bị tắt
+		if !account.IsSyncEnabled() {
+			continue
+		}
 		accountsPool <- struct{}{}
 		accountWg.Add(1)
 		go func(accountCopy models.RegisteredAccount) {
@@ -51,23 +64,59 @@ func (s *TradeHistoryService) FetchAllAccountTradeHistory(ctx context.Context) e
 	return nil
 }
 
-// Fetch trade history cho một account
+// Fetch trade history cho một account, tiếp tục từ trạng thái đồng bộ đã lưu
 func (s *TradeHistoryService) FetchAllTradeHistory(ctx context.Context, account models.RegisteredAccount) error {
-	var start time.Time
-	latestOrder, err := s.orderRepository.GetLatestOrder(ctx, account.ID, account.Exchange, account.Market)
-	if err == nil && latestOrder != nil && !latestOrder.Time.IsZero() {
-		start = latestOrder.Time
+	cursors, err := s.loadSyncCursors(ctx, account)
+	if err != nil {
+		log.Printf("Load sync state error %s: %v", account.Username, err)
+		return err
 	}
-	s.handleAccountTradeHistory(ctx, account, start)
+	s.handleAccountTradeHistory(ctx, account, cursors)
 	return nil
 }
 
-func (s *TradeHistoryService) handleAccountTradeHistory(ctx context.Context, account models.RegisteredAccount, start time.Time) {
+// loadSyncCursors lấy trạng thái đồng bộ của account. Account chưa có sync_state nhưng đã có
+// order (đồng bộ từ trước khi có sync_state) thì dựng cursor từ các order đã lưu. Symbol đã có
+// order nhưng chưa có cursor được thêm với cursor rỗng để exchange đồng bộ theo symbol (Binance
+// spot/margin) vẫn lấy symbol đó khi tài khoản không còn giữ base asset.
+func (s *TradeHistoryService) loadSyncCursors(ctx context.Context, account models.RegisteredAccount) (exchanges.SyncCursors, error) {
+	states, err := s.syncStateRepository.GetStates(ctx, account.ID, account.Exchange, account.Market)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		states, err = s.orderRepository.GetSymbolCursors(ctx, account.ID, account.Exchange, account.Market)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cursors := exchanges.SyncCursors{}
+	for _, state := range states {
+		cursors[state.Symbol] = state
+	}
+	symbols, err := s.orderRepository.GetSymbols(ctx, account.ID, account.Exchange, account.Market)
+	if err != nil {
+		return nil, err
+	}
+	for _, symbol := range symbols {
+		if _, ok := cursors[symbol]; !ok {
+			cursors[symbol] = models.SyncState{
+				RegisteredAccountID: account.ID,
+				Exchange:            account.Exchange,
+				Market:              account.Market,
+				Symbol:              symbol,
+			}
+		}
+	}
+	return cursors, nil
+}
+
+func (s *TradeHistoryService) handleAccountTradeHistory(ctx context.Context, account models.RegisteredAccount, cursors exchanges.SyncCursors) {
 	clientsInfo, err := s.clientManager.GetOrCreateClient(account)
 	if err != nil {
 		log.Printf("Get client error %s: %v", account.Username, err)
+		s.markFailure(ctx, account, err)
 		return
-		// Fetch trade history cho một account
 	}
 	clientPoolSize := 3
 	clientPool := make(chan struct{}, clientPoolSize)
@@ -80,20 +129,141 @@ func (s *TradeHistoryService) handleAccountTradeHistory(ctx context.Context, acc
 				<-clientPool
 				clientWg.Done()
 			}()
-			s.handleClientTradeHistory(ctx, clientCopy, account, start)
+			s.handleClientTradeHistory(ctx, clientCopy, account, cursors)
 		}(client)
 	}
 	clientWg.Wait()
 }
 
-func (s *TradeHistoryService) handleClientTradeHistory(ctx context.Context, client exchanges.ExchangeFetcher, account models.RegisteredAccount, start time.Time) {
-	orders, err := client.FetchTrades(ctx, account.ID, start)
-	if err != nil {
+func (s *TradeHistoryService) handleClientTradeHistory(ctx context.Context, client exchanges.ExchangeFetcher, account models.RegisteredAccount, cursors exchanges.SyncCursors) {
+	syncStartedAt := time.Now()
+	orders, err := client.FetchTrades(ctx, account.ID, cursors)
+	var symbolErrs exchanges.SymbolErrors
+	if err != nil && !errors.As(err, &symbolErrs) {
 		log.Println("FetchOrders error for account", account.Username, ":", err)
+		s.markFailure(ctx, account, err)
 		return
 	}
-	err = s.orderRepository.SaveOrders(orders)
-	if err != nil {
+	if err := s.saveOrders(ctx, account, orders); err != nil {
 		log.Println("Save orders error:", err)
+		s.markFailure(ctx, account, err)
+		return
+	}
+	if len(symbolErrs) > 0 {
+		// Trade của các symbol thành công đã được lưu, symbol lỗi giữ nguyên cursor và
+		// synced_until không đổi để lần sau lấy lại
+		log.Println("FetchOrders partially failed for account", account.Username, ":", symbolErrs)
+		s.markFailure(ctx, account, symbolErrs)
+		return
+	}
+	if fetcher, ok := client.(exchanges.MarketCursorFetcher); ok && fetcher.UsesMarketCursor() {
+		// Cursor chung của market chỉ tiến lên khi mọi fill của lần đồng bộ đã được lưu, nếu một
+		// batch lỗi thì lần sau lấy lại từ cursor cũ thay vì bỏ qua fill của batch đó
+		if err := s.syncStateRepository.SaveCursors(ctx, marketCursor(account, orders)); err != nil {
+			log.Println("Save market cursor error for account", account.Username, ":", err)
+			s.markFailure(ctx, account, err)
+			return
+		}
+	}
+	if err := s.syncStateRepository.MarkSuccess(ctx, account.ID, account.Exchange, account.Market, syncStartedAt); err != nil {
+		log.Println("Mark sync success error for account", account.Username, ":", err)
+	}
+}
+
+// saveOrders ghi trade theo từng symbol, mỗi lô tradeSaveBatchSize trade được ghi cùng cursor
+// của lô trong một transaction. Trade được sắp theo thời gian nên cursor đã lưu không bao giờ
+// vượt quá dữ liệu đã lưu kể cả khi lô sau bị lỗi.
+func (s *TradeHistoryService) saveOrders(ctx context.Context, account models.RegisteredAccount, orders []models.Order) error {
+	bySymbol := make(map[string][]models.Order)
+	var symbols []string
+	for _, order := range orders {
+		if _, ok := bySymbol[order.Symbol]; !ok {
+			symbols = append(symbols, order.Symbol)
+		}
+		bySymbol[order.Symbol] = append(bySymbol[order.Symbol], order)
+	}
+	for _, symbol := range symbols {
+		symbolOrders := bySymbol[symbol]
+		sort.SliceStable(symbolOrders, func(i, j int) bool { return symbolOrders[i].Time.Before(symbolOrders[j].Time) })
+		for start := 0; start < len(symbolOrders); start += tradeSaveBatchSize {
+			end := start + tradeSaveBatchSize
+			if end > len(symbolOrders) {
+				end = len(symbolOrders)
+			}
+			batch := symbolOrders[start:end]
+			err := s.syncStateRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+				if err := s.orderRepository.SaveOrders(txCtx, batch); err != nil {
+					return err
+				}
+				return s.syncStateRepository.SaveCursors(txCtx, symbolCursors(account, batch))
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *TradeHistoryService) markFailure(ctx context.Context, account models.RegisteredAccount, syncErr error) {
+	err := s.syncStateRepository.MarkFailure(ctx, account.ID, account.Exchange, account.Market, syncErr)
+	if err != nil {
+		log.Println("Mark sync failure error for account", account.Username, ":", err)
+	}
+}
+
+// marketCursor tính trade ID và thời gian lớn nhất của cả market, rỗng nếu không có order
+func marketCursor(account models.RegisteredAccount, orders []models.Order) []models.SyncState {
+	states := symbolCursors(account, orders)
+	if len(states) == 0 {
+		return nil
+	}
+	market := models.SyncState{
+		RegisteredAccountID: account.ID,
+		Exchange:            account.Exchange,
+		Market:              account.Market,
+	}
+	for _, state := range states {
+		if state.LastTradeID > market.LastTradeID {
+			market.LastTradeID = state.LastTradeID
+		}
+		if state.LastTradeTime.After(market.LastTradeTime) {
+			market.LastTradeTime = state.LastTradeTime
+		}
+	}
+	return []models.SyncState{market}
+}
+
+// symbolCursors tính trade ID và thời gian lớn nhất theo symbol trong danh sách order vừa lấy
+func symbolCursors(account models.RegisteredAccount, orders []models.Order) []models.SyncState {
+	bySymbol := make(map[string]*models.SyncState)
+	var symbols []string
+	for _, order := range orders {
+		tradeID, err := strconv.ParseInt(order.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		state, ok := bySymbol[order.Symbol]
+		if !ok {
+			state = &models.SyncState{
+				RegisteredAccountID: account.ID,
+				Exchange:            account.Exchange,
+				Market:              account.Market,
+				Symbol:              order.Symbol,
+			}
+			bySymbol[order.Symbol] = state
+			symbols = append(symbols, order.Symbol)
+		}
+		if tradeID > state.LastTradeID {
+			state.LastTradeID = tradeID
+		}
+		if order.Time.After(state.LastTradeTime) {
+			state.LastTradeTime = order.Time
+		}
+	}
+	states := make([]models.SyncState, len(symbols))
+	for i, symbol := range symbols {
+		states[i] = *bySymbol[symbol]
 	}
+	return states
 }
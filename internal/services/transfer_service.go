@@ -0,0 +1,112 @@
+package services
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// TransferSyncMarket là market dùng trong sync_state để lưu mốc đồng bộ nạp/rút/chuyển tiền,
+// tách khỏi cursor trade của market
+const TransferSyncMarket = "transfers"
+
+type TransferService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	transferRepository          *repositories.TransferRepository
+	syncStateRepository         *repositories.SyncStateRepository
+	clientManager               *ClientManagerService
+}
+
+func NewTransferService(registeredAccountRepository *repositories.RegisteredAccountRepository, transferRepository *repositories.TransferRepository, syncStateRepository *repositories.SyncStateRepository, clientManager *ClientManagerService) *TransferService {
+	return &TransferService{
+		registeredAccountRepository: registeredAccountRepository,
+		transferRepository:          transferRepository,
+		syncStateRepository:         syncStateRepository,
+		clientManager:               clientManager,
+	}
+}
+
+// SyncAllTransfers đồng bộ nạp/rút/chuyển tiền cho mọi tài khoản đang active có hỗ trợ
+func (s *TransferService) SyncAllTransfers(ctx context.Context) error {
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	accountsPool := make(chan struct{}, 5)
+	var accountWg sync.WaitGroup
+	for _, account := range accounts {
+		if !account.IsSyncEnabled() {
+			continue
+		}
+		accountsPool <- struct{}{}
+		accountWg.Add(1)
+		go func(accountCopy models.RegisteredAccount) {
+			defer func() {
+				<-accountsPool
+				accountWg.Done()
+			}()
+			err := s.SyncTransfers(ctx, accountCopy)
+			if err != nil && !errors.Is(err, exchanges.ErrTransfersNotSupported) {
+				log.Println("Sync transfers error for account", accountCopy.Username, ":", err)
+			}
+		}(account)
+	}
+	accountWg.Wait()
+	return nil
+}
+
+// SyncTransfers lấy nạp/rút/chuyển tiền mới từ mốc đã đồng bộ lần trước, dữ liệu và mốc mới
+// được ghi trong cùng transaction để mốc không vượt quá dữ liệu đã lưu
+func (s *TransferService) SyncTransfers(ctx context.Context, account models.RegisteredAccount) error {
+	fetcher, err := s.clientManager.CreateTransferFetcher(account)
+	if err != nil {
+		if !errors.Is(err, exchanges.ErrTransfersNotSupported) {
+			s.markFailure(ctx, account, err)
+		}
+		return err
+	}
+	states, err := s.syncStateRepository.GetStates(ctx, account.ID, account.Exchange, TransferSyncMarket)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	for _, state := range states {
+		if state.Symbol == "" {
+			since = state.SyncedUntil
+		}
+	}
+	syncStartedAt := time.Now()
+	transfers, err := fetcher.FetchTransfers(ctx, account.ID, since)
+	if err != nil {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	err = s.syncStateRepository.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.transferRepository.SaveTransfers(txCtx, transfers); err != nil {
+			return err
+		}
+		return s.syncStateRepository.MarkSuccess(txCtx, account.ID, account.Exchange, TransferSyncMarket, syncStartedAt)
+	})
+	if err != nil {
+		s.markFailure(ctx, account, err)
+		return err
+	}
+	return nil
+}
+
+func (s *TransferService) markFailure(ctx context.Context, account models.RegisteredAccount, syncErr error) {
+	err := s.syncStateRepository.MarkFailure(ctx, account.ID, account.Exchange, TransferSyncMarket, syncErr)
+	if err != nil {
+		log.Println("Mark transfer sync failure error for account", account.Username, ":", err)
+	}
+}
+
+// GetTransfers lấy nạp/rút/chuyển tiền của account trong kỳ [start, end), transferType rỗng là mọi loại
+func (s *TransferService) GetTransfers(ctx context.Context, account models.RegisteredAccount, transferType string, start, end time.Time) ([]models.Transfer, error) {
+	return s.transferRepository.GetTransfersInRange(ctx, account.ID, transferType, start, end)
+}
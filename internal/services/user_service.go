@@ -0,0 +1,85 @@
+package services
+
+import (
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+var (
+	ErrUsernameTaken      = errors.New("username already taken")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrWeakPassword       = errors.New("password too short")
+)
+
+type UserService struct {
+	userRepository *repositories.UserRepository
+}
+
+func NewUserService(userRepository *repositories.UserRepository) *UserService {
+	return &UserService{userRepository: userRepository}
+}
+
+// Signup tạo user mới với mật khẩu đã hash bằng bcrypt
+func (s *UserService) Signup(ctx context.Context, username, password string) (models.User, error) {
+	if len(password) < minPasswordLength {
+		return models.User{}, ErrWeakPassword
+	}
+	if _, err := s.userRepository.GetUserByUsername(ctx, username); err == nil {
+		return models.User{}, ErrUsernameTaken
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return models.User{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return models.User{}, err
+	}
+	user := models.User{
+		ID:           primitive.NewObjectID(),
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         models.RoleUser,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.userRepository.SaveUser(ctx, user); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.User{}, ErrUsernameTaken
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// Authenticate kiểm tra username/mật khẩu, user chưa đặt mật khẩu không đăng nhập được bằng cách này
+func (s *UserService) Authenticate(ctx context.Context, username, password string) (models.User, error) {
+	user, err := s.userRepository.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.User{}, ErrInvalidCredentials
+		}
+		return models.User{}, err
+	}
+	if user.PasswordHash == "" {
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return models.User{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, id primitive.ObjectID) (models.User, error) {
+	return s.userRepository.GetUserByID(ctx, id)
+}
+
+func (s *UserService) SetRole(ctx context.Context, id primitive.ObjectID, role string) error {
+	return s.userRepository.UpdateRole(ctx, id, role)
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"autobackcom/internal/exchanges"
+	"autobackcom/internal/models"
+	"autobackcom/internal/repositories"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Binance hủy listen key sau 60 phút nếu không được keepalive
+	userStreamKeepalive = 30 * time.Minute
+	// Chu kỳ đối chiếu danh sách tài khoản để mở/đóng stream
+	userStreamRefresh = 5 * time.Minute
+	// Thời gian chờ tối đa giữa các lần kết nối lại
+	userStreamMaxBackoff = 5 * time.Minute
+	// Kết nối giữ được lâu hơn mốc này thì lần kết nối lại sau bắt đầu chờ từ đầu
+	userStreamStableAfter = time.Minute
+)
+
+// ErrUserStreamDisconnected là lỗi websocket bị ngắt, stream sẽ được kết nối lại
+var ErrUserStreamDisconnected = errors.New("user data stream disconnected")
+
+// UserStreamsEnabled đọc USER_STREAM_ENABLED, mặc định bật
+func UserStreamsEnabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("USER_STREAM_ENABLED"))
+	return err != nil || enabled
+}
+
+// userStream là stream đang chạy của một tài khoản, credentialsVersion dùng để nhận ra key đã bị
+// thay (ciphertext không đổi với backend Vault nên không so sánh được bằng EncryptedAPIKey)
+type userStream struct {
+	cancel             context.CancelFunc
+	credentialsVersion int64
+}
+
+// UserStreamService giữ một user data stream cho mỗi tài khoản đang active có hỗ trợ stream,
+// fill nhận qua stream được upsert ngay vào orders. Cursor đồng bộ chỉ do REST cập nhật nên
+// fill bị lỡ khi mất kết nối được lấy lại bằng REST catch-up sau khi kết nối lại.
+type UserStreamService struct {
+	registeredAccountRepository *repositories.RegisteredAccountRepository
+	orderRepository             *repositories.OrderRepository
+	tradeHistoryService         *TradeHistoryService
+	clientManager               *ClientManagerService
+
+	mutex   sync.Mutex
+	streams map[primitive.ObjectID]userStream
+}
+
+func NewUserStreamService(registeredAccountRepository *repositories.RegisteredAccountRepository, orderRepository *repositories.OrderRepository, tradeHistoryService *TradeHistoryService, clientManager *ClientManagerService) *UserStreamService {
+	return &UserStreamService{
+		registeredAccountRepository: registeredAccountRepository,
+		orderRepository:             orderRepository,
+		tradeHistoryService:         tradeHistoryService,
+		clientManager:               clientManager,
+		streams:                     make(map[primitive.ObjectID]userStream),
+	}
+}
+
+// Run đối chiếu danh sách tài khoản định kỳ cho tới khi ctx kết thúc
+func (s *UserStreamService) Run(ctx context.Context) {
+	ticker := time.NewTicker(userStreamRefresh)
+	defer ticker.Stop()
+	for {
+		if err := s.refresh(ctx); err != nil {
+			log.Println("Refresh user data streams error:", err)
+		}
+		select {
+		case <-ctx.Done():
+			s.stopAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh mở stream cho tài khoản mới, đóng stream của tài khoản đã xóa/tạm dừng
+// và mở lại stream của tài khoản vừa đổi key
+func (s *UserStreamService) refresh(ctx context.Context) error {
+	// Giữ lock cả khi đọc danh sách để Stop chạy giữa chừng không bị refresh mở lại stream
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	accounts, err := s.registeredAccountRepository.GetAllRegisteredAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[primitive.ObjectID]struct{})
+	for _, account := range accounts {
+		if !account.IsSyncEnabled() || !s.clientManager.SupportsUserStream(account) {
+			continue
+		}
+		wanted[account.ID] = struct{}{}
+		if stream, ok := s.streams[account.ID]; ok {
+			if stream.credentialsVersion == account.CredentialsVersion {
+				continue
+			}
+			stream.cancel()
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		s.streams[account.ID] = userStream{cancel: cancel, credentialsVersion: account.CredentialsVersion}
+		go s.runStream(streamCtx, account)
+	}
+	for id, stream := range s.streams {
+		if _, ok := wanted[id]; !ok {
+			stream.cancel()
+			delete(s.streams, id)
+		}
+	}
+	return nil
+}
+
+// Stop đóng ngay stream của tài khoản vừa bị xóa, tạm dừng hoặc thay key thay vì chờ lần refresh sau
+func (s *UserStreamService) Stop(accountID primitive.ObjectID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if stream, ok := s.streams[accountID]; ok {
+		stream.cancel()
+		delete(s.streams, accountID)
+	}
+}
+
+func (s *UserStreamService) stopAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, stream := range s.streams {
+		stream.cancel()
+		delete(s.streams, id)
+	}
+}
+
+// runStream giữ stream của một tài khoản, kết nối lại với thời gian chờ tăng dần khi bị ngắt
+func (s *UserStreamService) runStream(ctx context.Context, account models.RegisteredAccount) {
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		connectedAt := time.Now()
+		// Lần kết nối đầu không cần catch-up vì cron REST đã chạy khi khởi động
+		err := s.serve(ctx, account, attempt > 0)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("User data stream of account %s stopped: %v", account.Username, err)
+		if time.Since(connectedAt) > userStreamStableAfter {
+			backoff = time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > userStreamMaxBackoff {
+			backoff = userStreamMaxBackoff
+		}
+	}
+}
+
+// serve mở một kết nối stream và chạy tới khi bị ngắt, listen key hết hạn hoặc ctx kết thúc
+func (s *UserStreamService) serve(ctx context.Context, account models.RegisteredAccount, catchUp bool) error {
+	streamer, err := s.clientManager.CreateUserStreamer(account)
+	if err != nil {
+		return err
+	}
+	listenKey, err := streamer.StartUserStream(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.registeredAccountRepository.UpdateListenKey(ctx, account.ID, listenKey); err != nil {
+		log.Printf("Save listen key error for account %s: %v", account.Username, err)
+	}
+
+	errC := make(chan error, 1)
+	doneC, stopC, err := streamer.ServeUserStream(account.ID, listenKey,
+		func(order models.Order) {
+			if err := s.orderRepository.SaveOrders(ctx, []models.Order{order}); err != nil {
+				log.Printf("Save streamed trade %s error for account %s: %v", order.ID, account.Username, err)
+			}
+		},
+		func(err error) {
+			select {
+			case errC <- err:
+			default:
+			}
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer close(stopC)
+
+	if catchUp {
+		// Stream đã kết nối nên fill mới không bị lỡ, REST lấy lại fill trong lúc mất kết nối
+		go func() {
+			if err := s.tradeHistoryService.FetchAllTradeHistory(ctx, account); err != nil {
+				log.Printf("REST catch-up error for account %s: %v", account.Username, err)
+			}
+		}()
+	}
+
+	keepalive := time.NewTicker(userStreamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.closeListenKey(account, streamer, listenKey)
+			return ctx.Err()
+		case <-doneC:
+			return ErrUserStreamDisconnected
+		case err := <-errC:
+			if errors.Is(err, exchanges.ErrListenKeyExpired) {
+				return err
+			}
+			log.Printf("User data stream error for account %s: %v", account.Username, err)
+		case <-keepalive.C:
+			if err := streamer.KeepaliveUserStream(ctx, listenKey); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// closeListenKey đóng listen key khi dừng stream, dùng context riêng vì ctx của stream đã kết thúc
+func (s *UserStreamService) closeListenKey(account models.RegisteredAccount, streamer exchanges.UserStreamer, listenKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := streamer.CloseUserStream(ctx, listenKey); err != nil {
+		log.Printf("Close listen key error for account %s: %v", account.Username, err)
+	}
+	// Chỉ xóa khi document vẫn giữ key này, stream mới mở cho tài khoản có thể đã ghi key khác
+	if err := s.registeredAccountRepository.ClearListenKey(ctx, account.ID, listenKey); err != nil {
+		log.Printf("Clear listen key error for account %s: %v", account.Username, err)
+	}
+}
@@ -5,31 +5,154 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 )
 
-var encryptionKey []byte
+// ID của key đọc từ ENCRYPTION_KEY, dùng cho ciphertext cũ không có tiền tố
+const legacyKeyID = "legacy"
 
-func getEncryptionKey() []byte {
-	if encryptionKey == nil {
-		key := os.Getenv("ENCRYPTION_KEY")
-		if key == "" {
-			panic("ENCRYPTION_KEY environment variable is not set")
+// Keyring chứa các key mã hóa theo ID, ciphertext mới luôn dùng key active.
+// Ciphertext có dạng "<keyID>:<base64(nonce|dữ liệu)>"; ciphertext cũ không có tiền tố
+// được giải mã bằng key legacy.
+type Keyring struct {
+	keys     map[string][]byte
+	activeID string
+}
+
+var (
+	keyring     *Keyring
+	keyringOnce sync.Once
+)
+
+// getKeyring đọc keyring từ env khi dùng lần đầu:
+// ENCRYPTION_KEYS="v1:<key>,v2:<key>", ENCRYPTION_ACTIVE_KEY_ID="v2" (mặc định key cuối danh sách).
+// ENCRYPTION_KEY nếu có được thêm với ID "legacy" và là key active khi không có ENCRYPTION_KEYS.
+func getKeyring() *Keyring {
+	keyringOnce.Do(func() {
+		ring, err := LoadKeyring(os.Getenv("ENCRYPTION_KEYS"), os.Getenv("ENCRYPTION_ACTIVE_KEY_ID"), os.Getenv("ENCRYPTION_KEY"))
+		if err != nil {
+			panic(err)
+		}
+		keyring = ring
+	})
+	return keyring
+}
+
+// LoadKeyring dựng keyring từ danh sách key, active key và key legacy
+func LoadKeyring(keys, activeID, legacyKey string) (*Keyring, error) {
+	ring := &Keyring{keys: make(map[string][]byte)}
+	lastID := ""
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
 		}
-		encryptionKey = []byte(key)
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || key == "" {
+			return nil, fmt.Errorf("invalid ENCRYPTION_KEYS entry %q, expected <id>:<key>", entry)
+		}
+		if _, exists := ring.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate encryption key id %q", id)
+		}
+		if err := checkKeySize(id, []byte(key)); err != nil {
+			return nil, err
+		}
+		ring.keys[id] = []byte(key)
+		lastID = id
+	}
+	if legacyKey != "" {
+		if _, exists := ring.keys[legacyKeyID]; !exists {
+			if err := checkKeySize(legacyKeyID, []byte(legacyKey)); err != nil {
+				return nil, err
+			}
+			ring.keys[legacyKeyID] = []byte(legacyKey)
+		}
+		if lastID == "" {
+			lastID = legacyKeyID
+		}
+	}
+	if len(ring.keys) == 0 {
+		return nil, errors.New("ENCRYPTION_KEYS or ENCRYPTION_KEY environment variable is not set")
 	}
-	return encryptionKey
+	if activeID == "" {
+		activeID = lastID
+	}
+	if _, exists := ring.keys[activeID]; !exists {
+		return nil, fmt.Errorf("active encryption key %q is not in the keyring", activeID)
+	}
+	ring.activeID = activeID
+	return ring, nil
 }
 
-func Encrypt(plainText string) (string, error) {
-	key := getEncryptionKey()
-	block, err := aes.NewCipher(key)
+func checkKeySize(id string, key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	default:
+		return fmt.Errorf("encryption key %q must be 16, 24 or 32 bytes", id)
+	}
+}
+
+// ActiveKeyID trả về ID của key đang dùng để mã hóa
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt mã hóa bằng key active và gắn ID key vào đầu ciphertext
+func (k *Keyring) Encrypt(plainText string) (string, error) {
+	encoded, err := EncryptWithKey(k.keys[k.activeID], plainText)
 	if err != nil {
 		return "", err
 	}
-	gcm, err := cipher.NewGCM(block)
+	return k.activeID + ":" + encoded, nil
+}
+
+// Decrypt giải mã bằng key ghi trong tiền tố của ciphertext
+func (k *Keyring) Decrypt(cipherText string) (string, error) {
+	id, data, ok := strings.Cut(cipherText, ":")
+	if !ok {
+		// Ciphertext trước khi có keyring
+		id, data = legacyKeyID, cipherText
+	}
+	key, exists := k.keys[id]
+	if !exists {
+		return "", fmt.Errorf("unknown encryption key id %q", id)
+	}
+	return decryptWithKey(key, data)
+}
+
+// KeyID trả về ID key đã mã hóa ciphertext
+func (k *Keyring) KeyID(cipherText string) string {
+	if id, _, ok := strings.Cut(cipherText, ":"); ok {
+		return id
+	}
+	return legacyKeyID
+}
+
+// Reencrypt giải mã rồi mã hóa lại bằng key active, trả về changed=false nếu đã dùng key active
+func (k *Keyring) Reencrypt(cipherText string) (string, bool, error) {
+	if cipherText == "" || k.KeyID(cipherText) == k.activeID {
+		return cipherText, false, nil
+	}
+	plainText, err := k.Decrypt(cipherText)
+	if err != nil {
+		return "", false, err
+	}
+	reencrypted, err := k.Encrypt(plainText)
+	if err != nil {
+		return "", false, err
+	}
+	return reencrypted, true, nil
+}
+
+// EncryptWithKey mã hóa AES-GCM bằng key cho trước, kết quả là base64(nonce|dữ liệu)
+func EncryptWithKey(key []byte, plainText string) (string, error) {
+	gcm, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
@@ -41,17 +164,25 @@ func Encrypt(plainText string) (string, error) {
 	return base64.StdEncoding.EncodeToString(cipherText), nil
 }
 
-func Decrypt(cipherText string) (string, error) {
-	key := getEncryptionKey()
-	data, err := base64.StdEncoding.DecodeString(cipherText)
+// DecryptWithKey giải mã dữ liệu tạo bởi EncryptWithKey
+func DecryptWithKey(key []byte, encoded string) (string, error) {
+	return decryptWithKey(key, encoded)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	block, err := aes.NewCipher(key)
+	return cipher.NewGCM(block)
+}
+
+func decryptWithKey(key []byte, encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
 	if err != nil {
 		return "", err
 	}
-	gcm, err := cipher.NewGCM(block)
+	gcm, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
@@ -66,3 +197,16 @@ func Decrypt(cipherText string) (string, error) {
 	}
 	return string(plainText), nil
 }
+
+// DefaultKeyring trả về keyring đọc từ env
+func DefaultKeyring() *Keyring {
+	return getKeyring()
+}
+
+func Encrypt(plainText string) (string, error) {
+	return getKeyring().Encrypt(plainText)
+}
+
+func Decrypt(cipherText string) (string, error) {
+	return getKeyring().Decrypt(cipherText)
+}